@@ -0,0 +1,172 @@
+package bsql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// MaxArgs is the max number of parameters of a statement postgres supports.
+const MaxArgs = 65535
+
+// ErrTooManyArgs is returned by Args.Err if more than MaxArgs values are collected.
+var ErrTooManyArgs = errors.New("bsql: too many args, postgres supports at most 65535 parameters")
+
+// Args collect values as placeholders "$n", an alternative to inlining literals into sql by V.
+// Its methods mirror V, Values, StructValues, etc, so the sql text stays the same for
+// different values, which keeps plan caching and pg_stat_statements grouping working.
+// The sql and List() can be passed to Query or Exec directly:
+//
+//	var args bsql.Args
+//	sql := "INSERT INTO users (id, name) VALUES " + args.StructValues(users, []string{"Id", "Name"})
+//	if err := args.Err(); err != nil {
+//		return err
+//	}
+//	_, err := db.Exec(sql, args.List()...)
+type Args struct {
+	list []interface{}
+	err  error
+}
+
+// V collect a value and return its placeholder, with a type cast if V renders one.
+// Raw and the literals of registered encoders are returned as they are, without collecting.
+func (a *Args) V(i interface{}) string {
+	if raw, ok := i.(Raw); ok {
+		return string(raw)
+	}
+	if literal, ok := encode(i); ok {
+		return literal
+	}
+	if a.err != nil {
+		return ""
+	}
+	if len(a.list) >= MaxArgs {
+		a.err = ErrTooManyArgs
+		return ""
+	}
+	arg, cast := argValue(i)
+	a.list = append(a.list, arg)
+	return "$" + strconv.Itoa(len(a.list)) + cast
+}
+
+// Values is like Values, but return placeholders.
+func (a *Args) Values(data interface{}) string {
+	return values(data, a.V)
+}
+
+// SingleColumnValues is like SingleColumnValues, but return placeholders.
+func (a *Args) SingleColumnValues(data interface{}) string {
+	return singleColumnValues(data, a.V)
+}
+
+// StructValues is like StructValues, but return placeholders, such as "($1,$2),($3,$4)".
+func (a *Args) StructValues(data interface{}, fields []string) string {
+	return structValues(data, fields, a.V, false)
+}
+
+// StructValuesOmitZero is like StructValuesOmitZero, but return placeholders.
+func (a *Args) StructValuesOmitZero(data interface{}, fields []string) string {
+	return structValues(data, fields, a.V, true)
+}
+
+// StructFieldValues is like StructFieldValues, but return placeholders, such as "($1,$2)".
+func (a *Args) StructFieldValues(data interface{}, field string) string {
+	return structFieldValues(data, field, a.V)
+}
+
+// Set is like Set, but return placeholders.
+func (a *Args) Set(data interface{}) string {
+	return set(data, a.V)
+}
+
+// List return the collected values, in the order of the placeholders.
+func (a *Args) List() []interface{} {
+	return a.list
+}
+
+// Len return the number of collected values.
+func (a *Args) Len() int {
+	return len(a.list)
+}
+
+// Err return ErrTooManyArgs if more than MaxArgs values are collected.
+// The sql built after the error is incomplete and must not be run.
+func (a *Args) Err() error {
+	return a.err
+}
+
+// argValue convert a value to what the driver accepts, in the same way as V renders it.
+func argValue(i interface{}) (interface{}, string) {
+	switch v := i.(type) {
+	case SecretValue:
+		arg, cast := argValue(v.value)
+		return Secret(arg), cast
+	case []byte, time.Time:
+		return v, ""
+	case json.RawMessage:
+		if v == nil {
+			return nil, ""
+		}
+		return string(v), ""
+	case time.Duration:
+		return interval(v), "::interval"
+	case net.IP:
+		if v == nil {
+			return nil, ""
+		}
+		return v.String(), "::inet"
+	case net.IPNet:
+		return v.String(), "::cidr"
+	case *net.IPNet:
+		if v == nil {
+			return nil, ""
+		}
+		return v.String(), "::cidr"
+	case big.Int:
+		return v.String(), "::numeric"
+	case *big.Int:
+		if v == nil {
+			return nil, ""
+		}
+		return v.String(), "::numeric"
+	case driver.Valuer, nil:
+		return v, ""
+	}
+
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, ""
+		}
+		return argValue(v.Elem().Interface())
+	case reflect.Array:
+		if isUUID(v.Type()) {
+			return uuid(v), "::uuid"
+		}
+		if array, ok := arrayLiteral(v); ok {
+			return array, ""
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, ""
+		}
+		if array, ok := arrayLiteral(v); ok {
+			return array, ""
+		}
+	case reflect.Map, reflect.Struct:
+	default:
+		return i, ""
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		log.Panic("bsql json.Marshal: ", err)
+	}
+	return string(b), ""
+}
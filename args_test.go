@@ -0,0 +1,55 @@
+package bsql
+
+import (
+	"fmt"
+	"time"
+)
+
+func ExampleArgs() {
+	type user struct {
+		Id       int
+		Name     string
+		Password string `sql:"secret"`
+	}
+	var args Args
+	sql := "INSERT INTO users (id, name, password) VALUES " + args.StructValues(
+		[]user{{1, "jack", "123"}, {2, "rose", "456"}}, []string{"Id", "Name", "Password"},
+	) + " ON CONFLICT (id) DO UPDATE SET updated_at = " + args.V(Raw("now()"))
+	fmt.Println(sql)
+	fmt.Println(args.List(), args.Err())
+	// Output:
+	// INSERT INTO users (id, name, password) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (id) DO UPDATE SET updated_at = now()
+	// [1 jack *** 2 rose ***] <nil>
+}
+
+func ExampleArgs_V() {
+	var args Args
+	fmt.Println(args.V(1), args.V("a"), args.V(time.Hour), args.V([]int64{1, 2}),
+		args.V(map[string]int{"a": 1}), args.V((*int)(nil)), args.V([16]byte{}))
+	fmt.Printf("%#v\n", args.List())
+	// Output:
+	// $1 $2 $3::interval $4 $5 $6 $7::uuid
+	// []interface {}{1, "a", "01:00:00", "{1,2}", "{\"a\":1}", interface {}(nil), "00000000-0000-0000-0000-000000000000"}
+}
+
+func ExampleArgs_Values() {
+	var args Args
+	fmt.Println(args.Values([][]interface{}{{1, "a"}, {2, "b"}}), args.SingleColumnValues([]int{3, 4}))
+	fmt.Println(args.StructFieldValues([]struct{ Id int }{{5}, {6}}, "Id"))
+	fmt.Println(args.List())
+	// Output:
+	// ($1,$2),($3,$4) ($5),($6)
+	// ($7,$8)
+	// [1 a 2 b 3 4 5 6]
+}
+
+func ExampleArgs_Err() {
+	var args Args
+	sql := args.SingleColumnValues(make([]int, MaxArgs))
+	fmt.Println(args.Len(), args.Err(), sql[len(sql)-8:])
+	args.V(1)
+	fmt.Println(args.Len(), args.Err())
+	// Output:
+	// 65535 <nil> ($65535)
+	// 65535 bsql: too many args, postgres supports at most 65535 parameters
+}
@@ -0,0 +1,156 @@
+// Package bsqltest provides a fake bsql.DbOrTx for unit tests without a database.
+//
+// The fake is a real *bsql.DB backed by an in-memory database/sql driver,
+// so canned rows are decoded through the real scan machinery,
+// and struct mapping bugs still show up in unit tests.
+package bsqltest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lovego/bsql"
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+// Fake implements bsql.DbOrTx and RunInTransaction by the embedded *bsql.DB.
+type Fake struct {
+	*bsql.DB
+	// If InOrder is true (the default), statements must be run in the order they are expected.
+	InOrder bool
+
+	mutex        sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+}
+
+// Call is a statement run on the fake.
+// Transaction control is recorded as a Call with SQL "BEGIN", "COMMIT" or "ROLLBACK".
+type Call struct {
+	SQL  string
+	Args []interface{}
+}
+
+func New() *Fake {
+	f := &Fake{InOrder: true}
+	f.DB = bsql.New(memdriver.Open(handler{f}), time.Minute)
+	return f
+}
+
+// Expect add an expectation of a statement matched by matcher.
+// Every expectation is consumed by exactly one statement.
+func (f *Fake) Expect(matcher Matcher) *Expectation {
+	e := &Expectation{matcher: matcher}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.expectations = append(f.expectations, e)
+	return e
+}
+
+// Calls return all the statements run on the fake.
+func (f *Fake) Calls() []Call {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// ExpectationsWereMet return an error describing the unmet expectations if any.
+func (f *Fake) ExpectationsWereMet() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var unmet []string
+	for _, e := range f.expectations {
+		if !e.met {
+			unmet = append(unmet, "  "+e.String())
+		}
+	}
+	if len(unmet) > 0 {
+		return errors.New("bsqltest: unmet expectations:\n" + strings.Join(unmet, "\n"))
+	}
+	return nil
+}
+
+func (f *Fake) record(sql string, args []interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls = append(f.calls, Call{SQL: sql, Args: args})
+}
+
+func (f *Fake) match(sql string, args []interface{}) (*Expectation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls = append(f.calls, Call{SQL: sql, Args: args})
+
+	for _, e := range f.expectations {
+		if e.met {
+			continue
+		}
+		if e.matches(sql, args) {
+			e.met = true
+			return e, nil
+		}
+		if f.InOrder {
+			return nil, fmt.Errorf(
+				"bsqltest: unexpected sql: %s\nargs: %#v\nnext expected: %s", sql, args, e,
+			)
+		}
+	}
+	return nil, fmt.Errorf("bsqltest: unexpected sql: %s\nargs: %#v", sql, args)
+}
+
+// Expectation is an expected statement and its canned result.
+type Expectation struct {
+	matcher      Matcher
+	args         []interface{}
+	argsMatcher  func(args []interface{}) bool
+	data         interface{}
+	err          error
+	rowsAffected *int64
+	met          bool
+}
+
+// WithArgs make the expectation only match statements with the exact args.
+func (e *Expectation) WithArgs(args ...interface{}) *Expectation {
+	e.args = args
+	e.argsMatcher = func(actual []interface{}) bool {
+		return len(args) == 0 && len(actual) == 0 || reflect.DeepEqual(args, actual)
+	}
+	return e
+}
+
+// Returns set the rows to return. data can be a Rows, a []map[string]interface{},
+// a struct or a slice of structs, a scalar or a slice of scalars.
+func (e *Expectation) Returns(data interface{}) *Expectation {
+	e.data = data
+	return e
+}
+
+// ReturnsError make the statement fail with err.
+func (e *Expectation) ReturnsError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// RowsAffected set the rows affected of Exec, it defaults to the number of rows returned.
+func (e *Expectation) RowsAffected(n int64) *Expectation {
+	e.rowsAffected = &n
+	return e
+}
+
+func (e *Expectation) String() string {
+	if e.argsMatcher != nil {
+		return fmt.Sprintf("%s with args %#v", e.matcher, e.args)
+	}
+	return e.matcher.String()
+}
+
+func (e *Expectation) matches(sql string, args []interface{}) bool {
+	if !e.matcher.Match(sql) {
+		return false
+	}
+	return e.argsMatcher == nil || e.argsMatcher(args)
+}
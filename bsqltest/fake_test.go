@@ -0,0 +1,154 @@
+package bsqltest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lovego/bsql"
+	"github.com/shopspring/decimal"
+)
+
+type Student struct {
+	Id        int64
+	Name      string
+	Cities    []string
+	Money     decimal.Decimal
+	UpdatedAt *time.Time
+}
+
+func ExampleFake() {
+	fake := New()
+	fake.Expect(SQL(`select * from students where id = $1`)).WithArgs(1).Returns(Student{
+		Id: 1, Name: "李雷", Cities: []string{"成都", "上海"}, Money: decimal.New(2504, -2),
+	})
+	fake.Expect(SQLRegexp(`^update students`)).RowsAffected(2)
+
+	var db bsql.DbOrTx = fake
+	var student Student
+	if err := db.Query(&student, `select * from students where id = $1`, 1); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%+v\n", student)
+
+	result, err := db.Exec(`update students set name = name`)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(result.RowsAffected())
+	fmt.Println(fake.ExpectationsWereMet())
+	// Output:
+	// {Id:1 Name:李雷 Cities:[成都 上海] Money:25.04 UpdatedAt:<nil>}
+	// 2 <nil>
+	// <nil>
+}
+
+type Base struct {
+	CreatedBy string
+}
+
+type Item struct {
+	Id int64
+	*Base
+}
+
+func ExampleFake_nilEmbedded() {
+	fake := New()
+	fake.Expect(SQL(`select * from items`)).Returns([]Item{{Id: 1}, {Id: 2, Base: &Base{CreatedBy: "Lili"}}})
+	var items []Item
+	if err := fake.Query(&items, `select * from items`); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(items[0].Id, items[0].Base, items[1].Id, *items[1].Base)
+	// Output:
+	// 1 <nil> 2 {Lili}
+}
+
+func ExampleFake_maps() {
+	fake := New()
+	fake.Expect(SQLFingerprint(`select id, name from students where id in (1, 2)`)).Returns(
+		[]map[string]interface{}{{"id": 3, "name": "Tom"}, {"id": 4, "name": nil}},
+	)
+	var students []Student
+	if err := fake.Query(
+		&students, `SELECT id, name FROM students WHERE id IN (3,4,5)`,
+	); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%+v\n", students)
+	// Output:
+	// [{Id:3 Name:Tom Cities:[] Money:0 UpdatedAt:<nil>} {Id:4 Name: Cities:[] Money:0 UpdatedAt:<nil>}]
+}
+
+func ExampleFake_scalars() {
+	fake := New()
+	fake.Expect(SQL(`select name from students`)).Returns([]string{"李雷", "韩梅梅"})
+	var names []string
+	if err := fake.Query(&names, `select name from students`); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(names)
+	// Output:
+	// [李雷 韩梅梅]
+}
+
+func ExampleFake_order() {
+	fake := New()
+	fake.Expect(SQL(`select 1`))
+	fake.Expect(SQL(`select 2`))
+
+	var i int
+	fmt.Println(fake.Query(&i, `select 2`) != nil)
+	fmt.Println(fake.ExpectationsWereMet())
+
+	fake = New()
+	fake.InOrder = false
+	fake.Expect(SQL(`select 1`)).Returns(1)
+	fake.Expect(SQL(`select 2`)).Returns(2)
+	fmt.Println(fake.Query(&i, `select 2`), i)
+	fmt.Println(fake.Query(&i, `select 1`), i)
+	fmt.Println(fake.ExpectationsWereMet())
+	// Output:
+	// true
+	// bsqltest: unmet expectations:
+	//   sql: select 1
+	//   sql: select 2
+	// <nil> 2
+	// <nil> 1
+	// <nil>
+}
+
+func ExampleFake_RunInTransaction() {
+	fake := New()
+	fake.Expect(SQL(`insert into students (name) values ($1)`)).WithArgs("Tom")
+	fake.Expect(SQL(`delete from students`)).ReturnsError(errors.New("permission denied"))
+
+	err := fake.RunInTransaction(func(tx *bsql.Tx) error {
+		if _, err := tx.Exec(`insert into students (name) values ($1)`, "Tom"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`delete from students`)
+		return err
+	})
+	fmt.Println(err)
+	for _, call := range fake.Calls() {
+		fmt.Println(call.SQL, call.Args)
+	}
+	// Output:
+	// permission denied
+	// BEGIN []
+	// insert into students (name) values ($1) [Tom]
+	// delete from students []
+	// ROLLBACK []
+}
+
+func ExampleFingerprint() {
+	fmt.Println(Fingerprint(`
+		SELECT * FROM students -- comment
+		WHERE id IN (1, 2, 3) AND name = 'it''s' AND money > $1
+	`))
+	fmt.Println(Fingerprint(`insert into t (a, b) values (1, 'a'), (2, 'b')`))
+	// Output:
+	// select * from students where id in (?) and name = ? and money > ?
+	// insert into t (a, b) values (?)
+}
@@ -0,0 +1,56 @@
+package bsqltest
+
+import (
+	"database/sql/driver"
+
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+// handler serves the statements run on a Fake by its expectations.
+type handler struct {
+	fake *Fake
+}
+
+func (h handler) Query(query string, args []interface{}) (*memdriver.Rows, error) {
+	e, err := h.fake.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return ToRows(e.data)
+}
+
+func (h handler) Exec(query string, args []interface{}) (driver.Result, error) {
+	e, err := h.fake.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.rowsAffected != nil {
+		return driver.RowsAffected(*e.rowsAffected), nil
+	}
+	rows, err := ToRows(e.data)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(rows.Values)), nil
+}
+
+func (h handler) Begin() error {
+	h.fake.record("BEGIN", nil)
+	return nil
+}
+
+func (h handler) Commit() error {
+	h.fake.record("COMMIT", nil)
+	return nil
+}
+
+func (h handler) Rollback() error {
+	h.fake.record("ROLLBACK", nil)
+	return nil
+}
@@ -0,0 +1,82 @@
+package bsqltest
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matcher decides if a statement is the expected one.
+type Matcher interface {
+	Match(sql string) bool
+	String() string
+}
+
+// SQL match a statement by exact text, ignoring leading and trailing spaces.
+func SQL(sql string) Matcher {
+	return exactMatcher(strings.TrimSpace(sql))
+}
+
+type exactMatcher string
+
+func (m exactMatcher) Match(sql string) bool {
+	return strings.TrimSpace(sql) == string(m)
+}
+
+func (m exactMatcher) String() string {
+	return "sql: " + string(m)
+}
+
+// SQLRegexp match a statement by a regular expression.
+func SQLRegexp(pattern string) Matcher {
+	return regexpMatcher{regexp.MustCompile(pattern)}
+}
+
+type regexpMatcher struct {
+	*regexp.Regexp
+}
+
+func (m regexpMatcher) Match(sql string) bool {
+	return m.MatchString(sql)
+}
+
+func (m regexpMatcher) String() string {
+	return "regexp: " + m.Regexp.String()
+}
+
+// SQLFingerprint match a statement by the fingerprint of sql, see Fingerprint.
+func SQLFingerprint(sql string) Matcher {
+	return fingerprintMatcher(Fingerprint(sql))
+}
+
+type fingerprintMatcher string
+
+func (m fingerprintMatcher) Match(sql string) bool {
+	return Fingerprint(sql) == string(m)
+}
+
+func (m fingerprintMatcher) String() string {
+	return "fingerprint: " + string(m)
+}
+
+var (
+	commentRegexp     = regexp.MustCompile(`--[^\n]*|/\*[\s\S]*?\*/`)
+	stringRegexp      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberRegexp      = regexp.MustCompile(`(^|[^\w$])-?\d+(?:\.\d+)?\b`)
+	placeholderRegexp = regexp.MustCompile(`\$\d+`)
+	spaceRegexp       = regexp.MustCompile(`\s+`)
+	listRegexp        = regexp.MustCompile(`\?(?: ?, ?\?)+`)
+	rowsRegexp        = regexp.MustCompile(`\(\?\)(?: ?, ?\(\?\))+`)
+)
+
+// Fingerprint normalize a sql, so statements only differ in literals or placeholders
+// have the same fingerprint: comments are removed, literals and placeholders are replaced by "?",
+// lists of "?" and rows of "(?)" are collapsed, spaces are collapsed and letters are lowercased.
+func Fingerprint(sql string) string {
+	sql = commentRegexp.ReplaceAllString(sql, " ")
+	sql = stringRegexp.ReplaceAllString(sql, "?")
+	sql = numberRegexp.ReplaceAllString(sql, "$1?")
+	sql = placeholderRegexp.ReplaceAllString(sql, "?")
+	sql = strings.ToLower(strings.TrimSpace(spaceRegexp.ReplaceAllString(sql, " ")))
+	sql = listRegexp.ReplaceAllString(sql, "?")
+	return rowsRegexp.ReplaceAllString(sql, "(?)")
+}
@@ -0,0 +1,44 @@
+package bsqltest
+
+import (
+	"encoding/json"
+
+	"github.com/lovego/bsql"
+)
+
+// Replay return a Fake serving the records in a fixture file written by a bsql.Recorder.
+// Statements must be run in the recorded order, with the same sql and args.
+// The recorded column types are reproduced, so JSON and array columns are scanned the same way.
+func Replay(path string) (*Fake, error) {
+	records, err := bsql.LoadRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	f := New()
+	for _, record := range records {
+		rows, err := record.DriverRows()
+		if err != nil {
+			return nil, err
+		}
+		e := f.Expect(SQL(record.SQL)).Returns(Rows{
+			Columns: record.Columns, Types: record.Types, Values: rows,
+		}).RowsAffected(record.RowsAffected)
+		e.args, e.argsMatcher = record.Args, jsonArgsMatcher(record.Args)
+		if record.Error != nil {
+			e.ReturnsError(record.Error.Err())
+		}
+	}
+	return f, nil
+}
+
+// jsonArgsMatcher compare args by their JSON, because recorded args lost their Go types.
+func jsonArgsMatcher(recorded []interface{}) func([]interface{}) bool {
+	expected, _ := json.Marshal(recorded)
+	return func(args []interface{}) bool {
+		if len(recorded) == 0 && len(args) == 0 {
+			return true
+		}
+		actual, err := json.Marshal(args)
+		return err == nil && string(actual) == string(expected)
+	}
+}
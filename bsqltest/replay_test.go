@@ -0,0 +1,71 @@
+package bsqltest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lovego/bsql"
+)
+
+func ExampleReplay() {
+	dir, err := ioutil.TempDir("", "bsqltest")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "students.json")
+
+	type student struct {
+		Id        int64
+		Name      string
+		Cities    []string
+		Scores    map[string]int
+		CreatedAt time.Time
+	}
+	// record against the fake, as if it's a real database.
+	fake := New()
+	fake.Expect(SQL(`select * from students where id = $1`)).WithArgs(1).Returns(Rows{
+		Columns: []string{"id", "name", "cities", "scores", "created_at"},
+		Types:   []string{"INT8", "TEXT", "_TEXT", "JSONB", "TIMESTAMPTZ"},
+		Values: [][]interface{}{{
+			1, "李雷", []byte(`{成都,上海}`), []byte(`{"语文":99}`),
+			time.Date(2001, 9, 1, 12, 25, 48, 0, time.UTC),
+		}},
+	})
+	fake.Expect(SQL(`update students set name = 'Tom'`)).ReturnsError(&pq.Error{
+		Message: "duplicate key value", Code: "23505", Constraint: "students_name_key",
+	})
+	recorder := bsql.NewRecorder(path)
+	db := &bsql.DB{DB: fake.DB.DB, Timeout: time.Second, Recorder: recorder}
+
+	var got student
+	fmt.Println(db.Query(&got, `select * from students where id = $1`, 1))
+	_, err = db.Exec(`update students set name = 'Tom'`)
+	fmt.Println(err)
+	if err := recorder.Close(); err != nil {
+		panic(err)
+	}
+
+	// replay without the fake.
+	replay, err := Replay(path)
+	if err != nil {
+		panic(err)
+	}
+	var replayed student
+	fmt.Println(replay.Query(&replayed, `select * from students where id = $1`, 1))
+	fmt.Printf("%+v\n", replayed)
+	_, err = replay.Exec(`update students set name = 'Tom'`)
+	fmt.Println(err, bsql.ConflictedUniqueIndex(err))
+	fmt.Println(replay.ExpectationsWereMet())
+	// Output:
+	// <nil>
+	// pq: duplicate key value
+	// <nil>
+	// {Id:1 Name:李雷 Cities:[成都 上海] Scores:map[语文:99] CreatedAt:2001-09-01 12:25:48 +0000 UTC}
+	// pq: duplicate key value students_name_key
+	// <nil>
+}
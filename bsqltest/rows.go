@@ -0,0 +1,216 @@
+package bsqltest
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/lovego/bsql"
+	"github.com/lovego/bsql/internal/memdriver"
+	"github.com/lovego/value"
+)
+
+// Rows is the canned result of a statement.
+type Rows struct {
+	Columns []string
+	// Types are the DatabaseTypeName of the columns, eg. "JSONB", "_TEXT".
+	// If not provided, they are inferred from values.
+	Types  []string
+	Values [][]interface{}
+}
+
+// ToRows convert data into Rows, see Expectation.Returns for the supported data.
+func ToRows(data interface{}) (*memdriver.Rows, error) {
+	var rows *Rows
+	switch v := data.(type) {
+	case nil:
+		return &memdriver.Rows{}, nil
+	case Rows:
+		rows = &v
+	case *Rows:
+		rows = v
+	case []map[string]interface{}:
+		rows = mapsToRows(v)
+	case map[string]interface{}:
+		rows = mapsToRows([]map[string]interface{}{v})
+	default:
+		rows = valuesToRows(reflect.ValueOf(data))
+	}
+	return rows.toDriverValues()
+}
+
+func mapsToRows(maps []map[string]interface{}) *Rows {
+	var columns []string
+	var set = make(map[string]bool)
+	for _, m := range maps {
+		for column := range m {
+			if !set[column] {
+				set[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	rows := &Rows{Columns: columns}
+	for _, m := range maps {
+		row := make([]interface{}, len(columns))
+		for i, column := range columns {
+			row[i] = m[column]
+		}
+		rows.Values = append(rows.Values, row)
+	}
+	return rows
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func valuesToRows(v reflect.Value) *Rows {
+	var elems []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			elems = []reflect.Value{v}
+		} else {
+			for i := 0; i < v.Len(); i++ {
+				elems = append(elems, v.Index(i))
+			}
+		}
+	default:
+		elems = []reflect.Value{v}
+	}
+
+	var elemType reflect.Type
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		elemType = v.Type().Elem()
+	} else {
+		elemType = v.Type()
+	}
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct || elemType == timeType ||
+		reflect.PtrTo(elemType).Implements(valuerType) {
+		rows := &Rows{Columns: []string{"value"}}
+		for _, elem := range elems {
+			rows.Values = append(rows.Values, []interface{}{elem.Interface()})
+		}
+		return rows
+	}
+
+	strct := reflect.Zero(elemType).Interface()
+	fields := bsql.FieldsFromStruct(strct, nil)
+	rows := &Rows{Columns: bsql.Fields2ColumnsOf(strct, fields)}
+	for _, elem := range elems {
+		row := make([]interface{}, len(fields))
+		for i, field := range fields {
+			row[i] = fieldValue(elem, field)
+		}
+		rows.Values = append(rows.Values, row)
+	}
+	return rows
+}
+
+// fieldValue return the value of the field, nil if it's promoted through a nil embedded struct pointer,
+// which is NULL as the columns of a LEFT JOIN without a match.
+func fieldValue(strct reflect.Value, name string) interface{} {
+	for strct.Kind() == reflect.Ptr || strct.Kind() == reflect.Interface {
+		if strct.IsNil() {
+			return nil
+		}
+		strct = strct.Elem()
+	}
+	field, ok := strct.Type().FieldByName(name)
+	if !ok {
+		return value.Get(strct, []string{name}).Interface()
+	}
+	for _, index := range field.Index {
+		for strct.Kind() == reflect.Ptr {
+			if strct.IsNil() {
+				return nil
+			}
+			strct = strct.Elem()
+		}
+		strct = strct.Field(index)
+	}
+	return strct.Interface()
+}
+
+func (rows *Rows) toDriverValues() (*memdriver.Rows, error) {
+	result := &memdriver.Rows{Columns: rows.Columns, Types: make([]string, len(rows.Columns))}
+	copy(result.Types, rows.Types)
+	for _, row := range rows.Values {
+		if len(row) != len(rows.Columns) {
+			return nil, fmt.Errorf(
+				"bsqltest: %d values in a row, but %d columns", len(row), len(rows.Columns),
+			)
+		}
+		values := make([]interface{}, len(row))
+		for i := range row {
+			v, typ, err := driverValue(row[i])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+			if result.Types[i] == "" {
+				result.Types[i] = typ
+			}
+		}
+		result.Values = append(result.Values, values)
+	}
+	return result, nil
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// driverValue convert v to a value returned by the postgres driver, and it's DatabaseTypeName.
+func driverValue(v interface{}) (driver.Value, string, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, "", nil
+	case []byte:
+		return x, "BYTEA", nil
+	case time.Time:
+		return x, "TIMESTAMPTZ", nil
+	case driver.Valuer:
+		if rv := reflect.ValueOf(x); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, "", nil
+		}
+		dv, err := x.Value()
+		if err != nil {
+			return nil, "", err
+		}
+		if s, ok := dv.(string); ok {
+			return []byte(s), "", nil
+		}
+		return driverValue(dv)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), "TEXT", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), "INT8", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), "INT8", nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), "FLOAT8", nil
+	case reflect.Bool:
+		return rv.Bool(), "BOOL", nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, "", nil
+		}
+		return driverValue(rv.Elem().Interface())
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, "", err
+		}
+		return b, "JSONB", nil
+	}
+}
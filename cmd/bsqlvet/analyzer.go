@@ -0,0 +1,398 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const bsqlPath = "github.com/lovego/bsql"
+
+// ignoreComment suppress the reports on its line, the next line,
+// and the bsql calls on its line or the next line.
+const ignoreComment = "bsqlvet:ignore"
+
+var Analyzer = &analysis.Analyzer{
+	Name: "bsqlvet",
+	Doc: `report unsafe sql string building
+
+The sql argument of the bsql DB and Tx methods is traced back to its construction.
+Non-constant strings concatenated into it by "+", fmt.Sprintf or strings.Join are reported,
+unless they are made by bsql helpers like Q, V, Array, Json, Values and StructValues.
+Add a "//` + ignoreComment + `" comment to suppress a report.`,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{
+		pass: pass, assigns: collectAssigns(pass), ignored: collectIgnored(pass),
+		reported: map[token.Pos]bool{},
+	}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if i := sqlArgIndex(pass.TypesInfo, call); i >= 0 && i < len(call.Args) {
+			c.call = call
+			c.check(call.Args[i], false, map[*types.Var]bool{}, nil)
+		}
+	})
+	return nil, nil
+}
+
+type assign struct {
+	expr   ast.Expr // nil if the value is unknown, such as a range variable.
+	concat bool     // "+=" assignment.
+}
+
+type checker struct {
+	pass     *analysis.Pass
+	assigns  map[*types.Var][]assign
+	ignored  map[string]map[int]bool // file name => line => true
+	reported map[token.Pos]bool
+	call     *ast.CallExpr
+}
+
+// check report the non-constant strings concatenated into expr, which aren't made by bsql helpers.
+// If expr is the value of a variable, at is the variable to report instead of expr.
+func (c *checker) check(expr ast.Expr, inConcat bool, visited map[*types.Var]bool, at ast.Expr) {
+	if c.isConstant(expr) || !isString(c.pass.TypesInfo.TypeOf(expr)) {
+		return
+	}
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		c.check(e.X, inConcat, visited, at)
+		return
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			c.check(e.X, true, visited, nil)
+			c.check(e.Y, true, visited, nil)
+			return
+		}
+	case *ast.IndexExpr:
+		c.check(e.X, inConcat, visited, at)
+		return
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			c.check(elt, inConcat, visited, nil)
+		}
+		return
+	case *ast.Ident:
+		if v, ok := c.pass.TypesInfo.Uses[e].(*types.Var); ok {
+			if assigns, ok := c.assigns[v]; ok {
+				if at == nil {
+					at = e
+				}
+				if visited[v] {
+					// a variable concatenated to itself, like: where = where + " AND " + cond
+					if hasUnknown(assigns) {
+						c.report(at, inConcat)
+					}
+					return
+				}
+				visited[v] = true
+				for _, a := range assigns {
+					if a.expr == nil {
+						c.report(at, inConcat)
+					} else if a.concat {
+						c.check(a.expr, true, visited, nil)
+					} else {
+						c.check(a.expr, inConcat, visited, at)
+					}
+				}
+				return
+			}
+		}
+	case *ast.CallExpr:
+		if c.checkCall(e, inConcat, visited, at) {
+			return
+		}
+	}
+	if at == nil {
+		at = expr
+	}
+	c.report(at, inConcat)
+}
+
+func hasUnknown(assigns []assign) bool {
+	for _, a := range assigns {
+		if a.expr == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// safeStrconv are the strconv formatters whose results are safe in sql.
+// Quote isn't, because sql doesn't unescape the Go escapes like \".
+var safeStrconv = map[string]bool{
+	"Itoa": true, "FormatInt": true, "FormatUint": true, "FormatFloat": true, "FormatBool": true,
+}
+
+// checkCall check the calls known to be safe or to concatenate strings,
+// return false for other calls.
+func (c *checker) checkCall(
+	call *ast.CallExpr, inConcat bool, visited map[*types.Var]bool, at ast.Expr,
+) bool {
+	info := c.pass.TypesInfo
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		// conversions to bsql.Raw are explicit, others are checked as their operand.
+		if isBsqlType(tv.Type) {
+			return true
+		}
+		if len(call.Args) == 1 && isString(info.TypeOf(call.Args[0])) {
+			c.check(call.Args[0], inConcat, visited, at)
+			return true
+		}
+		return false
+	}
+	if id, ok := call.Fun.(*ast.Ident); ok {
+		if b, ok := info.Uses[id].(*types.Builtin); ok && b.Name() == "append" {
+			for _, arg := range call.Args {
+				c.check(arg, inConcat, visited, nil)
+			}
+			return true
+		}
+	}
+
+	fn := callee(info, call)
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	switch path, name := fn.Pkg().Path(), fn.Name(); {
+	case path == bsqlPath:
+		// PatternEscape doesn't quote, it should be wrapped by Q.
+		return name != "PatternEscape"
+	case path == "strconv" && safeStrconv[name]:
+		return true
+	case path == "strings" && name == "Join":
+		c.check(call.Args[0], true, visited, nil)
+		c.check(call.Args[1], true, visited, nil)
+		return true
+	case path == "strings" && (name == "Repeat" || name == "ToUpper" || name == "ToLower" ||
+		name == "TrimSpace" || strings.HasPrefix(name, "Trim")):
+		c.check(call.Args[0], inConcat, visited, at)
+		return true
+	case path == "fmt" && name == "Sprintf" && !call.Ellipsis.IsValid():
+		c.checkSprintf(call, visited)
+		return true
+	}
+	return false
+}
+
+// checkSprintf check the arguments formatted by %s, %v or %q into the sql.
+func (c *checker) checkSprintf(call *ast.CallExpr, visited map[*types.Var]bool) {
+	format, args := call.Args[0], call.Args[1:]
+	c.check(format, false, visited, nil)
+	tv := c.pass.TypesInfo.Types[format]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		// the format is made by helpers like bsql.UpsertSql, check all the arguments.
+		for _, arg := range args {
+			c.check(arg, true, visited, nil)
+		}
+		return
+	}
+	verbs, ok := parseVerbs(constant.StringVal(tv.Value))
+	if !ok {
+		for _, arg := range args {
+			c.check(arg, true, visited, nil)
+		}
+		return
+	}
+	for i, verb := range verbs {
+		if i < len(args) && strings.ContainsRune("svq", verb) {
+			c.check(args[i], true, visited, nil)
+		}
+	}
+}
+
+// parseVerbs return the verb of each argument, false if explicit argument indexes are used.
+func parseVerbs(format string) ([]rune, bool) {
+	var verbs []rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format); i++ {
+			ch := format[i]
+			if ch == '[' {
+				return nil, false
+			}
+			if ch == '*' {
+				verbs = append(verbs, 'd')
+				continue
+			}
+			if strings.IndexByte("+-# 0.123456789", ch) < 0 {
+				break
+			}
+		}
+		if i < len(format) && format[i] != '%' {
+			verbs = append(verbs, rune(format[i]))
+		}
+	}
+	return verbs, true
+}
+
+func (c *checker) report(expr ast.Expr, inConcat bool) {
+	if !inConcat || c.reported[expr.Pos()] || c.isIgnored(expr.Pos()) || c.isIgnored(c.call.Pos()) {
+		return
+	}
+	c.reported[expr.Pos()] = true
+	c.pass.Reportf(expr.Pos(),
+		"non-constant string %s is put into sql without quoting by bsql.Q or bsql.V",
+		types.ExprString(expr),
+	)
+}
+
+func (c *checker) isIgnored(pos token.Pos) bool {
+	position := c.pass.Fset.Position(pos)
+	lines := c.ignored[position.Filename]
+	return lines[position.Line] || lines[position.Line-1]
+}
+
+func (c *checker) isConstant(expr ast.Expr) bool {
+	return c.pass.TypesInfo.Types[expr].Value != nil
+}
+
+// sqlArgIndex return the index of the "sql" parameter of bsql methods, -1 if not a bsql method.
+func sqlArgIndex(info *types.Info, call *ast.CallExpr) int {
+	fn := callee(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != bsqlPath {
+		return -1
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil {
+		return -1
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		if param := sig.Params().At(i); param.Name() == "sql" && isString(param.Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+func isString(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		return t.Info()&types.IsString != 0
+	case *types.Slice:
+		return isString(t.Elem())
+	case *types.Array:
+		return isString(t.Elem())
+	}
+	return false
+}
+
+func isBsqlType(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == bsqlPath
+}
+
+// collectAssigns collect the values assigned to string variables.
+func collectAssigns(pass *analysis.Pass) map[*types.Var][]assign {
+	assigns := map[*types.Var][]assign{}
+	add := func(id *ast.Ident, a assign) {
+		obj := pass.TypesInfo.Defs[id]
+		if obj == nil {
+			obj = pass.TypesInfo.Uses[id]
+		}
+		if v, ok := obj.(*types.Var); ok && isString(v.Type()) {
+			assigns[v] = append(assigns[v], a)
+		}
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					id, ok := ast.Unparen(lhs).(*ast.Ident)
+					if !ok {
+						continue
+					}
+					a := assign{concat: n.Tok == token.ADD_ASSIGN}
+					if len(n.Lhs) == len(n.Rhs) {
+						a.expr = n.Rhs[i]
+					}
+					add(id, a)
+				}
+			case *ast.ValueSpec:
+				for i, id := range n.Names {
+					if i < len(n.Values) {
+						add(id, assign{expr: n.Values[i]})
+					} else if len(n.Values) > 0 {
+						add(id, assign{})
+					}
+				}
+			case *ast.RangeStmt:
+				if id, ok := n.Key.(*ast.Ident); ok {
+					add(id, assign{})
+				}
+				if id, ok := n.Value.(*ast.Ident); ok {
+					// the elements of a string slice or array.
+					if isString(pass.TypesInfo.TypeOf(n.X)) {
+						add(id, assign{expr: n.X})
+					} else {
+						add(id, assign{})
+					}
+				}
+			case *ast.FuncType:
+				// parameters are unknown values.
+				if n.Params != nil {
+					for _, field := range n.Params.List {
+						for _, id := range field.Names {
+							add(id, assign{})
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	return assigns
+}
+
+func collectIgnored(pass *analysis.Pass) map[string]map[int]bool {
+	ignored := map[string]map[int]bool{}
+	for _, file := range pass.Files {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if !strings.Contains(comment.Text, ignoreComment) {
+					continue
+				}
+				position := pass.Fset.Position(comment.Pos())
+				if ignored[position.Filename] == nil {
+					ignored[position.Filename] = map[int]bool{}
+				}
+				ignored[position.Filename][position.Line] = true
+			}
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,14 @@
+// Command bsqlvet reports unsafe sql string building with bsql.
+//
+// Run it directly or as a vet tool:
+//
+//	go install github.com/lovego/bsql/cmd/bsqlvet@latest
+//	bsqlvet ./...
+//	go vet -vettool=$(which bsqlvet) ./...
+package main
+
+import "golang.org/x/tools/go/analysis/singlechecker"
+
+func main() {
+	singlechecker.Main(Analyzer)
+}
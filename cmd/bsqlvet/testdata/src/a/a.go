@@ -0,0 +1,67 @@
+package a
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lovego/bsql"
+)
+
+const usersSql = "SELECT * FROM users"
+
+func constant(db *bsql.DB, limit int) {
+	db.Query(nil, usersSql+" WHERE id = 1")
+	db.Query(nil, fmt.Sprintf("SELECT * FROM users LIMIT %d", limit))
+}
+
+func quoted(db *bsql.DB, tx *bsql.Tx, name string, ids []int64) {
+	db.Query(nil, "SELECT * FROM users WHERE name = "+bsql.Q(name))
+	tx.Query(nil, fmt.Sprintf("SELECT * FROM users WHERE id = ANY(%s)", bsql.Array(ids)))
+	db.Query(nil, "SELECT * FROM users WHERE name LIKE "+bsql.Q("%"+bsql.PatternEscape(name)+"%"))
+	db.Query(nil, "SELECT * FROM users WHERE created_at > "+string(bsql.Raw("now()")))
+}
+
+func concatenated(db *bsql.DB, name string) {
+	db.Query(nil, "SELECT * FROM users WHERE name = '"+name+"'")                          // want `non-constant string name is put into sql`
+	db.Exec(fmt.Sprintf("DELETE FROM users WHERE name = '%s'", name))                     // want `non-constant string name is put into sql`
+	db.Query(nil, "SELECT * FROM users WHERE name LIKE '%"+bsql.PatternEscape(name)+"%'") // want `non-constant string bsql.PatternEscape\(name\) is put into sql`
+}
+
+func formatted(db *bsql.DB, id int64, name string) {
+	db.Query(nil, "SELECT * FROM users WHERE id = "+strconv.FormatInt(id, 10))
+	db.Query(nil, "SELECT * FROM users WHERE name = "+strconv.Quote(name)) // want `non-constant string strconv.Quote\(name\) is put into sql`
+}
+
+func traced(db bsql.DbOrTx, name string, columns []string) {
+	where := "name = '" + name + "'" // want `non-constant string name is put into sql`
+	db.Query(nil, "SELECT * FROM users WHERE "+where)
+
+	conds := []string{"deleted = false"}
+	conds = append(conds, "name = "+bsql.Q(name))
+	db.Query(nil, "SELECT * FROM users WHERE "+strings.Join(conds, " AND "))
+
+	db.Query(nil, "SELECT "+strings.Join(columns, ", ")+" FROM users") // want `non-constant string columns is put into sql`
+
+	sql := "SELECT * FROM users WHERE true"
+	if name != "" {
+		sql += " AND name = " + bsql.V(name)
+	}
+	db.Query(nil, sql)
+}
+
+func upsert(db *bsql.DB, rows interface{}, values string) {
+	db.Exec(fmt.Sprintf(bsql.UpsertSql("users", nil, nil, nil), bsql.StructValues(rows, nil)))
+	db.Exec(fmt.Sprintf(bsql.UpsertSql("users", nil, nil, nil), values)) // want `non-constant string values is put into sql`
+}
+
+func ignored(db *bsql.DB, sortBy string) {
+	// sortBy is validated by the caller.
+	//bsqlvet:ignore
+	db.Query(nil, "SELECT * FROM users ORDER BY "+sortBy)
+	db.Query(nil, "SELECT * FROM users ORDER BY "+sortBy) //bsqlvet:ignore
+}
+
+func notSql(name string) error {
+	return bsql.WrapError(nil, "SELECT "+name)
+}
@@ -0,0 +1,28 @@
+// Package bsql is a stub of github.com/lovego/bsql for the tests.
+package bsql
+
+type DB struct{}
+
+func (db *DB) Query(data interface{}, sql string, args ...interface{}) error { return nil }
+func (db *DB) Exec(sql string, args ...interface{}) (interface{}, error)     { return nil, nil }
+
+type Tx struct{}
+
+func (tx *Tx) Query(data interface{}, sql string, args ...interface{}) error { return nil }
+
+type DbOrTx interface {
+	Query(data interface{}, sql string, args ...interface{}) error
+}
+
+type Raw string
+
+func Q(s string) string                     { return s }
+func V(i interface{}) string                { return "" }
+func Array(data interface{}) string         { return "" }
+func Json(data interface{}) string          { return "" }
+func PatternEscape(s string) string         { return s }
+func WrapError(err error, sql string) error { return err }
+
+func StructValues(data interface{}, fields []string) string { return "" }
+
+func UpsertSql(table string, toInsert, conflictKeys, notToUpdate []string) string { return "" }
@@ -17,11 +17,11 @@ func Columns2Fields(columns []string) (result []string) {
 }
 
 func ColumnsFromStruct(strct interface{}, exclude []string) (result []string) {
-	return Fields2Columns(FieldsFromStruct(strct, exclude))
+	return Fields2ColumnsOf(strct, FieldsFromStruct(strct, exclude))
 }
 
 func ColumnsStrFromStruct(strct interface{}, exclude []string) string {
-	return Fields2ColumnsStr(FieldsFromStruct(strct, exclude))
+	return Fields2ColumnsStrOf(strct, FieldsFromStruct(strct, exclude))
 }
 
 func ColumnsComments(table string, strct interface{}) (result string) {
@@ -32,7 +32,7 @@ func ColumnsComments(table string, strct interface{}) (result string) {
 		}
 		if comment != "" {
 			result += fmt.Sprintf(
-				"COMMENT ON COLUMN %s.%s IS %s;\n", table, Field2Column(field.Name), Q(comment),
+				"COMMENT ON COLUMN %s.%s IS %s;\n", table, scan.ColumnName(field), Q(comment),
 			)
 		}
 	})
@@ -1,11 +1,15 @@
 package bsql
 
 import (
+	"encoding/json"
+	"math/big"
+	"net"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/lovego/struct_tag"
+	"github.com/lovego/bsql/scan"
 )
 
 var typesMap = make(map[string]bool)
@@ -40,17 +44,15 @@ func ColumnsDefs(strct interface{}) string {
 func columnsFromStruct(model interface{}) []string {
 	columns := make([]string, 0)
 	traverseStructFields(reflect.TypeOf(model), func(field reflect.StructField) {
-		columns = append(columns, Field2Column(field.Name)+" "+getColumnDefinition(field))
+		columns = append(columns, scan.ColumnName(field)+" "+getColumnDefinition(field))
 	})
 	return columns
 }
 
 func getColumnDefinition(field reflect.StructField) string {
 	var def []string
-	tag, ok := struct_tag.Lookup(string(field.Tag), `sql`)
-	if ok {
-		tag = strings.TrimSpace(tag)
-	}
+	sqlTag := parseSqlTag(field.Tag)
+	tag := sqlTag.Def
 	if hasColumnType(tag) {
 		def = append(def, tag)
 	} else {
@@ -65,6 +67,9 @@ func getColumnDefinition(field reflect.StructField) string {
 	if tag != "" && tag != "-" && !hasColumnType(tag) {
 		def = append(def, tag)
 	}
+	if expr := sqlTag.Options["default"]; expr != "" && !defaultClauseRegexp.MatchString(tag) {
+		def = append(def, "DEFAULT "+expr)
+	}
 	return strings.Join(def, " ")
 }
 
@@ -85,11 +90,32 @@ func hasPrimaryKeyConstraint(s string) bool {
 	return primaryKeyConstraintRegexp.MatchString(s)
 }
 
+var (
+	durationType   = reflect.TypeOf(time.Duration(0))
+	ipType         = reflect.TypeOf(net.IP{})
+	ipNetType      = reflect.TypeOf(net.IPNet{})
+	bigIntType     = reflect.TypeOf(big.Int{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// getColumnType return the column type of a field, which accepts the literal rendered by V.
 func getColumnType(field reflect.StructField) string {
 	typ := field.Type
 	for typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
+	switch typ {
+	case durationType:
+		return "interval"
+	case ipType:
+		return "inet"
+	case ipNetType:
+		return "cidr"
+	case bigIntType:
+		return "numeric"
+	case rawMessageType:
+		return "jsonb"
+	}
 	switch typ.Kind() {
 	case reflect.String:
 		return "text"
@@ -132,7 +158,57 @@ func getColumnType(field reflect.StructField) string {
 		case "StringArray":
 			return "text[]"
 		default:
+			if kind := typ.Kind(); kind == reflect.Slice || kind == reflect.Array {
+				return getArrayColumnType(typ)
+			}
 			return "jsonb"
 		}
 	}
 }
+
+// getArrayColumnType return the column type of a slice or array, which V renders as a postgres array,
+// except []byte as bytea, [16]byte as uuid, and the slices of maps or structs as json.
+func getArrayColumnType(typ reflect.Type) string {
+	if typ.Elem().Kind() == reflect.Uint8 {
+		if isUUID(typ) {
+			return "uuid"
+		} else if typ.Kind() == reflect.Slice {
+			return "bytea"
+		}
+		return "jsonb"
+	}
+	elem := getColumnType(reflect.StructField{Type: typ.Elem()})
+	if elem == "jsonb" {
+		return "jsonb"
+	}
+	return strings.TrimSuffix(elem, "[]") + "[]"
+}
+
+var columnTypeRegexp = regexp.MustCompile(`(?i)^(?:double\s+precision|character\s+varying|\w+)` +
+	`(?:\s*\([^)]*\))?(?:\s+with(?:out)?\s+time\s+zone)?(?:\s*\[\d*\])*`)
+
+// fieldColumnType return the column type given by the sql tag of a field, or getColumnType.
+func fieldColumnType(field reflect.StructField) string {
+	if typ := parseSqlTag(field.Tag).ColumnType(); typ != "" {
+		return typ
+	}
+	return getColumnType(field)
+}
+
+// castType return the type to cast a value of the column type to, which is the integer type of a serial type.
+func castType(columnType string) string {
+	switch strings.ToLower(columnType) {
+	case "bigserial", "serial8":
+		return "int8"
+	case "serial", "serial4":
+		return "int4"
+	case "smallserial", "serial2":
+		return "int2"
+	}
+	return columnType
+}
+
+func isJsonType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	return columnType == "json" || columnType == "jsonb"
+}
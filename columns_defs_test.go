@@ -26,7 +26,7 @@ func ExampleColumnsDefs() {
 	// id serial8 NOT NULL PRIMARY KEY,
 	// name text NOT NULL,
 	// friend_ids int[] NOT NULL,
-	// cities jsonb NOT NULL,
+	// cities text[] NOT NULL,
 	// scores jsonb NOT NULL,
 	// money decimal NOT NULL,
 	// status int2 NOT NULL default 0,
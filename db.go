@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/lovego/bsql/position"
 	"github.com/lovego/bsql/scan"
 	"github.com/lovego/errs"
 	"github.com/lovego/tracer"
@@ -19,6 +20,18 @@ type DB struct {
 	Debug         bool
 	DebugOutput   io.Writer
 	PutSqlInError bool // put sql into returned error if error happend .
+	// redact literals inlined into sql in debug output, error data and tracer attributes.
+	// If nil, RedactSecrets is used.
+	SqlRedactor SqlRedactor
+	// lines of context and truncation of the error position put into error data.
+	ErrorContext position.Options
+	// map result columns to struct fields when scanning, and fields to columns in InsertStructs.
+	// The other sql helpers don't take a DB or Tx, so they use the global NameMapper.
+	// If nil, the global NameMapper is used. It must be comparable, because it keys the caches of scan plans.
+	NameMapper NameMapper
+	// settings applied by "SET LOCAL" in every transaction and statement, see WithSessionSettings.
+	SessionSettings map[string]string
+	Recorder        *Recorder // record statements and results into a fixture file if not nil.
 }
 
 func New(db *sql.DB, timeout time.Duration) *DB {
@@ -57,7 +70,9 @@ func (db *DB) QueryT(duration time.Duration, data interface{}, sql string, args
 func (db *DB) QueryCtx(ctx context.Context, opName string,
 	data interface{}, sql string, args ...interface{},
 ) error {
-	defer tracer.Finish(tracer.StartChild(ctx, opName))
+	ctx = tracer.StartChild(ctx, opName)
+	defer tracer.Finish(ctx)
+	traceSql(ctx, db.SqlRedactor, sql, args)
 	if ctx.Done() == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, db.Timeout)
@@ -79,7 +94,9 @@ func (db *DB) ExecT(duration time.Duration, sql string, args ...interface{}) (sq
 func (db *DB) ExecCtx(
 	ctx context.Context, opName string, sql string, args ...interface{},
 ) (sql.Result, error) {
-	defer tracer.Finish(tracer.StartChild(ctx, opName))
+	ctx = tracer.StartChild(ctx, opName)
+	defer tracer.Finish(ctx)
+	traceSql(ctx, db.SqlRedactor, sql, args)
 	if ctx.Done() == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, db.Timeout)
@@ -89,32 +106,37 @@ func (db *DB) ExecCtx(
 }
 
 func (db *DB) query(ctx context.Context, data interface{}, sql string, args []interface{}, reuse ...bool) error {
-	return run(db.Debug, db.DebugOutput, db.PutSqlInError, sql, args,
+	return run(db.runOptions(), sql, args,
 		func() (scanAt time.Time, err error) {
-			rows, err := db.DB.QueryContext(ctx, sql, args...)
-			if rows != nil {
-				defer rows.Close()
-			}
-			if err != nil {
-				return scanAt, errs.Trace(err)
-			}
-			if db.Debug {
-				scanAt = time.Now()
-			}
-			if err := scan.Scan(rows, data, reuse...); err != nil {
-				return scanAt, errs.Trace(err)
-			}
-			return scanAt, nil
+			err = db.inSession(ctx, func(q queryer) error {
+				rows, err := db.Recorder.queryContext(ctx, q, sql, args)
+				if rows != nil {
+					defer rows.Close()
+				}
+				if err != nil {
+					return errs.Trace(err)
+				}
+				if db.Debug {
+					scanAt = time.Now()
+				}
+				if err := scan.ScanWithMapper(rows, data, db.NameMapper, reuse...); err != nil {
+					return errs.Trace(err)
+				}
+				return nil
+			})
+			return scanAt, err
 		})
 }
 
 func (db *DB) exec(
 	ctx context.Context, sql string, args []interface{},
 ) (result sql.Result, err error) {
-	err = run(db.Debug, db.DebugOutput, db.PutSqlInError, sql, args,
+	err = run(db.runOptions(), sql, args,
 		func() (time.Time, error) {
-			result, err = db.DB.ExecContext(ctx, sql, args...)
-			return time.Time{}, errs.Trace(err)
+			return time.Time{}, db.inSession(ctx, func(q queryer) error {
+				result, err = db.Recorder.execContext(ctx, q, sql, args)
+				return errs.Trace(err)
+			})
 		})
 	return
 }
@@ -137,8 +159,14 @@ func (db *DB) RunInTransactionT(duration time.Duration, fn func(*Tx) error) erro
 			panic(err)
 		}
 	}()
+	if err := applySessionSettings(ctx, tx, db.SessionSettings); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 	if err := fn(&Tx{
 		Tx: tx, Context: db.Context, Timeout: db.Timeout, PutSqlInError: db.PutSqlInError,
+		Recorder: db.Recorder, SqlRedactor: db.SqlRedactor, ErrorContext: db.ErrorContext,
+		NameMapper: db.NameMapper,
 	}); err != nil {
 		_ = tx.Rollback()
 		return err
@@ -171,8 +199,14 @@ func (db *DB) RunInTransactionCtx(
 			panic(err)
 		}
 	}()
+	if err := applySessionSettings(ctx, tx, db.SessionSettings); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 	if err := fn(&Tx{
 		Tx: tx, Context: db.Context, Timeout: db.Timeout, PutSqlInError: db.PutSqlInError,
+		Recorder: db.Recorder, SqlRedactor: db.SqlRedactor, ErrorContext: db.ErrorContext,
+		NameMapper: db.NameMapper,
 	}, ctx); err != nil {
 		_ = tx.Rollback()
 		return err
@@ -190,3 +224,10 @@ func (db *DB) context(timeout time.Duration) (context.Context, context.CancelFun
 	}
 	return context.WithTimeout(ctx, timeout)
 }
+
+func (db *DB) runOptions() runOptions {
+	return runOptions{
+		debug: db.Debug, debugOutput: db.DebugOutput, putSqlInError: db.PutSqlInError,
+		sqlRedactor: db.SqlRedactor, errorContext: db.ErrorContext,
+	}
+}
@@ -43,29 +43,66 @@ func ErrorWithPosition(err error, sql string) error {
 	return err
 }
 
-func GetPosition(err error, sql string) string {
-	pqError, ok := err.(*pq.Error)
-	if !ok || pqError == nil {
+// GetPosition describe the error position in sql if err is a *pq.Error.
+// For errors inside functions, the call in sql and the position in the internal query
+// are both marked, followed by the traceback of pq.Error.Where.
+// opts are the context lines and truncation of the rendered position, see position.Render.
+func GetPosition(err error, sql string, opts ...position.Options) string {
+	return getPosition(err, sql, func(s string) string { return s }, opts...)
+}
+
+// getPosition is like GetPosition, but the sql is redacted before rendered,
+// so the marks and truncation apply to the redacted sql, and no secret is cut out of its mark.
+func getPosition(err error, sql string, redact SqlRedactor, opts ...position.Options) string {
+	var pqError *pq.Error
+	if !As(err, &pqError) || pqError == nil {
 		return ""
 	}
-	// Position: the field value is a decimal ASCII integer,
-	// indicating an error cursor position as an index into the original query string.
-	// The first character has index 1, and positions are measured in characters not bytes.
-	pos := pqError.Position
-	if pos == "" && pqError.InternalPosition != "" {
-		pos = pqError.InternalPosition
-		sql = pqError.InternalQuery
+	var options position.Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	frames := ParseWhere(pqError.Where)
+
+	var descs []string
+	if desc := renderPosition(sql, pqError.Position, options, redact); desc != "" {
+		descs = append(descs, desc)
+	} else if start, end := callSite(sql, frames); start >= 0 {
+		content := []rune(sql)
+		descs = append(descs, position.Render([]rune(redact(sql)),
+			redactedOffset(content, start, redact), redactedOffset(content, end, redact), options))
+	}
+	if desc := renderPosition(
+		pqError.InternalQuery, pqError.InternalPosition, options, redact,
+	); desc != "" {
+		descs = append(descs, "internal query:\n"+desc)
 	}
-	var positionDesc string
-	if pos != "" {
-		if offset, err := strconv.Atoi(pos); err == nil && offset >= 1 {
-			positionDesc = position.Get([]rune(sql), int(offset-1))
-		}
+	if traceback := Traceback(frames); traceback != "" {
+		descs = append(descs, redact(traceback))
 	}
-	if positionDesc != "" {
-		return positionDesc + "\n" + PrettyPrint(*pqError)
+	return strings.Join(append(descs, redact(PrettyPrint(*pqError))), "\n")
+}
+
+// Position: the field value is a decimal ASCII integer,
+// indicating an error cursor position as an index into the original query string.
+// The first character has index 1, and positions are measured in characters not bytes.
+func renderPosition(sql, pos string, options position.Options, redact SqlRedactor) string {
+	offset, err := strconv.Atoi(pos)
+	if err != nil || offset < 1 {
+		return ""
+	}
+	start := redactedOffset([]rune(sql), offset-1, redact)
+	content := []rune(redact(sql))
+	return position.Render(content, start, position.TokenEnd(content, start), options)
+}
+
+// redactedOffset map the offset in content to the offset in the redacted content,
+// which is the length of the redacted text before it.
+func redactedOffset(content []rune, offset int, redact SqlRedactor) int {
+	if offset < 0 || offset > len(content) {
+		return offset
 	}
-	return PrettyPrint(*pqError)
+	return len([]rune(redact(string(content[:offset]))))
 }
 
 func PrettyPrint(v interface{}) string {
@@ -2,6 +2,10 @@ package bsql
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/lovego/bsql/position"
 )
 
 func ExamplePrettyPrint() {
@@ -18,3 +22,36 @@ func ExamplePrettyPrint() {
 	// line2
 	// }
 }
+
+func ExampleGetPosition() {
+	sql := "select id,\n  agee\nfrom students\nwhere id = 1"
+	err := WrapError(&pq.Error{Message: `column "agee" does not exist`, Position: "14"}, sql, false)
+	desc := GetPosition(err, sql, position.Options{Before: 1, After: 1})
+	// the position is followed by the pretty printed *pq.Error.
+	fmt.Println(strings.Join(strings.Split(desc, "\n")[:4], "\n"))
+	// Output:
+	// 1 | select id,
+	// 2 |   agee
+	//   |   ^^^^
+	// 3 | from students
+}
+
+func ExampleGetPosition_internal() {
+	sql := "select id,\n  Add_Score(id)\nfrom students"
+	err := WrapError(&pq.Error{
+		Message:          `value 101 is out of range`,
+		InternalQuery:    "insert into scores values (id, 101)",
+		InternalPosition: "32",
+		Where:            "PL/pgSQL function add_score(integer) line 5 at SQL statement",
+	}, sql, false)
+	desc := GetPosition(err, sql)
+	fmt.Println(strings.Join(strings.Split(desc, "\n")[:7], "\n"))
+	// Output:
+	// 2 |   Add_Score(id)
+	//   |   ^^^^^^^^^
+	// internal query:
+	// 1 | insert into scores values (id, 101)
+	//   |                                ^^^
+	// Traceback (most recent call last):
+	//   PL/pgSQL function add_score(integer) line 5 at SQL statement
+}
@@ -26,7 +26,7 @@ type Student struct {
 	Id        int64
 	Name      string
 	FriendIds pq.Int64Array `sql:"int[]"`
-	Cities    []string
+	Cities    []string      `sql:"json"`
 	Scores    map[string]int
 	Money     decimal.Decimal
 	Status    int8 `sql:"default 0"`
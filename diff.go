@@ -0,0 +1,122 @@
+package bsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/lovego/bsql/scan"
+)
+
+// Change is a field changed between the old and new struct values.
+// Old and New of a `sql:"secret"` field are secret values.
+type Change struct {
+	Field  string
+	Column string
+	Old    interface{}
+	New    interface{}
+}
+
+// Changes is the result of Diff, in the order of the struct fields.
+type Changes []Change
+
+// Diff return the fields changed from old to new, which must be the same struct type.
+// Fields are walked as StructValues does, and compared by their sql literals,
+// so values rendered the same by V are equal, and times are compared as instants.
+func Diff(old, new interface{}) Changes {
+	oldValue, newValue := structValue(old), structValue(new)
+	if oldValue.Type() != newValue.Type() {
+		log.Panic("bsql: Diff old and new must be the same struct type.")
+	}
+	typ := newValue.Type()
+	var changes Changes
+	traverseStructFields(typ, func(field reflect.StructField) {
+		oldField, newField := getValue(oldValue, field.Name), getValue(newValue, field.Name)
+		if diffLiteral(oldField) == diffLiteral(newField) {
+			return
+		}
+		change := Change{
+			Field: field.Name, Column: scan.ColumnName(field), Old: oldField.Interface(), New: newField.Interface(),
+		}
+		if tag, ok := fieldTag(typ, field.Name); ok && tag.Has("secret") {
+			change.Old, change.New = Secret(change.Old), Secret(change.New)
+		}
+		changes = append(changes, change)
+	})
+	return changes
+}
+
+func structValue(data interface{}) reflect.Value {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		log.Panic("bsql: Diff data must be struct.")
+	}
+	return value
+}
+
+// diffLiteral return the sql literal to compare a field by.
+func diffLiteral(field reflect.Value) string {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return "NULL"
+		}
+		field = field.Elem()
+	}
+	if t, ok := field.Interface().(time.Time); ok {
+		return V(t.UTC())
+	}
+	return V(field.Interface())
+}
+
+// Fields return the changed fields.
+func (changes Changes) Fields() []string {
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+// UpdateSql return the sql to update only the changed columns to the values of data, see UpdateSql.
+// It return an empty string if there is no change to update.
+// The keyFields are expected to be unchanged.
+func (changes Changes) UpdateSql(table string, data interface{}, keyFields []string) string {
+	if len(changes) == 0 || len(fieldsToUpdate(structValue(data).Type(), changes.Fields(), keyFields)) == 0 {
+		return ""
+	}
+	return UpdateSql(table, data, changes.Fields(), keyFields)
+}
+
+// MarshalJSON return the change record, which is a json object of column names to the old and new values,
+// in the order of the struct fields, like: {"name":{"old":"Lili","new":"Lucy"}}.
+// It's suitable for inserting into an audit table by V.
+func (changes Changes) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, change := range changes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		column, err := json.Marshal(change.Column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(struct {
+			Old interface{} `json:"old"`
+			New interface{} `json:"new"`
+		}{change.Old, change.New})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(column)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
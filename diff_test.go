@@ -0,0 +1,39 @@
+package bsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func ExampleDiff() {
+	type user struct {
+		Id        int64
+		Name      string
+		Password  string `sql:"; secret"`
+		Balance   decimal.Decimal
+		LoginAt   time.Time
+		CreatedAt time.Time `sql:"readonly"`
+	}
+	loginAt := time.Date(2019, 6, 19, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	old := user{
+		Id: 1, Name: "Lili", Password: "123", Balance: decimal.RequireFromString("1.50"),
+		LoginAt: loginAt, CreatedAt: loginAt,
+	}
+	new := user{
+		Id: 1, Name: "Lucy", Password: "456", Balance: decimal.RequireFromString("1.5"),
+		LoginAt: loginAt.UTC(), CreatedAt: loginAt.Add(time.Hour),
+	}
+	changes := Diff(old, &new)
+	fmt.Println(changes.Fields())
+	fmt.Println(changes.UpdateSql("users", new, []string{"Id"}))
+	fmt.Println(V(changes))
+	fmt.Printf("%q\n", Diff(old, old).UpdateSql("users", old, []string{"Id"}))
+	// Output:
+	// [Name Password CreatedAt]
+	// UPDATE users SET name = 'Lucy', password = /*secret*/'456'
+	// WHERE id = 1
+	// '{"name":{"old":"Lili","new":"Lucy"},"password":{"old":"***","new":"***"},"created_at":{"old":"2019-06-19T08:00:00+08:00","new":"2019-06-19T09:00:00+08:00"}}'
+	// ""
+}
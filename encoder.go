@@ -0,0 +1,69 @@
+package bsql
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var encoders sync.Map // reflect.Type => func(reflect.Value) string
+
+// RegisterEncoder register a func to render the sql literal of values of typ,
+// which V, Values, StructValues and the like use, so domain types need not implement driver.Valuer.
+// It takes precedence over driver.Valuer and the builtin rules. Slices of typ are rendered as
+// ARRAY[...]. Args inlines the literal instead of using a placeholder. A nil encode unregisters typ.
+func RegisterEncoder(typ reflect.Type, encode func(reflect.Value) string) {
+	if typ == nil {
+		log.Panic("bsql: RegisterEncoder with nil type.")
+	}
+	if encode == nil {
+		encoders.Delete(typ)
+	} else {
+		encoders.Store(typ, encode)
+	}
+}
+
+func getEncoder(typ reflect.Type) func(reflect.Value) string {
+	if encode, ok := encoders.Load(typ); ok {
+		return encode.(func(reflect.Value) string)
+	}
+	return nil
+}
+
+// encode return the literal of i by the registered encoder, false if no encoder.
+func encode(i interface{}) (string, bool) {
+	if i == nil {
+		return "", false
+	}
+	v := reflect.ValueOf(i)
+	if encode := getEncoder(v.Type()); encode != nil {
+		return encode(v), true
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if encode := getEncoder(v.Type().Elem()); encode != nil {
+			if v.IsNil() {
+				return "NULL", true
+			}
+			return encode(v.Elem()), true
+		}
+	case reflect.Slice, reflect.Array:
+		if elemType := v.Type().Elem(); getEncoder(elemType) != nil ||
+			elemType.Kind() == reflect.Ptr && getEncoder(elemType.Elem()) != nil {
+			return encodeArray(v), true
+		}
+	}
+	return "", false
+}
+
+func encodeArray(v reflect.Value) string {
+	if v.Len() == 0 {
+		return "'{}'"
+	}
+	elems := make([]string, v.Len())
+	for i := range elems {
+		elems[i] = V(v.Index(i).Interface())
+	}
+	return "ARRAY[" + strings.Join(elems, ",") + "]"
+}
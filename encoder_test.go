@@ -0,0 +1,42 @@
+package bsql
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+type money int64
+
+type point struct{ X, Y float64 }
+
+func ExampleRegisterEncoder() {
+	RegisterEncoder(reflect.TypeOf(money(0)), func(v reflect.Value) string {
+		return strconv.FormatFloat(float64(v.Int())/100, 'f', 2, 64) + "::money"
+	})
+	RegisterEncoder(reflect.TypeOf(point{}), func(v reflect.Value) string {
+		p := v.Interface().(point)
+		return fmt.Sprintf("point(%g,%g)", p.X, p.Y)
+	})
+	defer RegisterEncoder(reflect.TypeOf(money(0)), nil)
+	defer RegisterEncoder(reflect.TypeOf(point{}), nil)
+
+	type shop struct {
+		Name     string
+		Location point
+		Balance  *money
+	}
+	balance := money(12345)
+	fmt.Println(V(balance), V(&balance), V((*money)(nil)))
+	fmt.Println(V([]money{1, 2}), V([]*money{}))
+	fmt.Println(StructValues([]shop{{"a", point{1, 2.5}, &balance}, {"b", point{}, nil}},
+		[]string{"Name", "Location", "Balance"}))
+
+	var args Args
+	fmt.Println(args.V("a"), args.V(point{3, 4}), args.List())
+	// Output:
+	// 123.45::money 123.45::money NULL
+	// ARRAY[0.01::money,0.02::money] '{}'
+	// ('a',point(1,2.5),123.45::money),('b',point(0,0),NULL)
+	// $1 point(3,4) [a]
+}
@@ -0,0 +1,110 @@
+package bsql
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Error is the details of an error returned by the Query and Exec methods of DB and Tx.
+// The returned error is an *errs.Error as before, whose GetError is the original error,
+// Code and Message are empty, and Data is the *Error, which is an error unwrapped to Err. Get it by As:
+//
+//	var e *bsql.Error
+//	if bsql.As(err, &e) { ... }
+type Error struct {
+	SQL     string   // the sql with literals redacted.
+	Args    []string // the redacted args.
+	Err     error    // the original error.
+	PqError *pq.Error
+	// Line and Column of the error position in the redacted SQL, begins at 1, 0 if unknown.
+	Line, Column int
+	Position     string // the rendered error position and *pq.Error.
+	// Where is the context stack of errors inside functions, the innermost comes first.
+	Where   []WhereFrame
+	Elapsed time.Duration
+	Caller  string // file:line of the Query or Exec call site.
+
+	putSqlInError bool
+}
+
+// Error return the message of the original error,
+// followed by the position and sql if PutSqlInError is true.
+func (e *Error) Error() string {
+	if e.putSqlInError {
+		return e.Err.Error() + "\n" + e.String()
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// String return the position, followed by the sql if PutSqlInError is true.
+func (e *Error) String() string {
+	if e.putSqlInError && e.Position != "" {
+		return e.Position + "\n" + e.SQL
+	} else if e.putSqlInError {
+		return e.SQL
+	}
+	return e.Position
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	var code string
+	if e.PqError != nil {
+		code = string(e.PqError.Code)
+	}
+	return json.Marshal(struct {
+		Message  string       `json:"message"`
+		Code     string       `json:"code,omitempty"`
+		SQL      string       `json:"sql,omitempty"`
+		Args     []string     `json:"args,omitempty"`
+		Line     int          `json:"line,omitempty"`
+		Column   int          `json:"column,omitempty"`
+		Position string       `json:"position,omitempty"`
+		Elapsed  string       `json:"elapsed"`
+		Caller   string       `json:"caller,omitempty"`
+		PqError  *pq.Error    `json:"pqError,omitempty"`
+		Where    []WhereFrame `json:"where,omitempty"`
+	}{
+		Message: e.Err.Error(), Code: code, Args: e.Args, Line: e.Line, Column: e.Column,
+		Position: e.Position, Elapsed: e.Elapsed.String(), Caller: e.Caller, PqError: e.PqError,
+		Where: e.Where,
+		SQL:   e.sqlIfPut(),
+	})
+}
+
+func (e *Error) sqlIfPut() string {
+	if e.putSqlInError {
+		return e.SQL
+	}
+	return ""
+}
+
+var bsqlDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// caller return the file:line of the first caller out of bsql and database/sql.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		inBsql := filepath.Dir(frame.File) == bsqlDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !inBsql && !strings.HasPrefix(frame.Function, "database/sql.") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
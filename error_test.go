@@ -0,0 +1,74 @@
+package bsql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lovego/errs"
+)
+
+func ExampleError() {
+	pqError := &pq.Error{
+		Code: "42703", Message: `column "agee" does not exist`, Position: "28",
+	}
+	sql := "select id\nfrom users\nwhere agee = 1"
+	err := run(runOptions{}, sql, []interface{}{1, Secret("token")},
+		func() (time.Time, error) { return time.Time{}, pqError },
+	)
+
+	traced := err.(*errs.Error)
+	fmt.Println(traced.GetError() == pqError, traced.Code() == "", traced.Message() == "")
+
+	var e *Error
+	fmt.Println(As(err, &e), Is(err, ErrUndefinedColumn), traced.Data() == e)
+	fmt.Println(e.SQL == sql, e.Args, e.Line, e.Column)
+	fmt.Println(e.PqError == pqError, e.Elapsed > 0, errors.Unwrap(e) == pqError)
+	fmt.Println(strings.HasPrefix(filepath.Base(e.Caller), "error_test.go:"))
+	fmt.Println(err)
+	// Output:
+	// true true true
+	// true true true
+	// true [1 ***] 3 7
+	// true true true
+	// true
+	// pq: column "agee" does not exist
+}
+
+func ExampleError_String() {
+	err := WrapError(errors.New("failed"), "select 1", true)
+	fmt.Println(err)
+	fmt.Println(err.(*errs.Error).Data().(*Error).String())
+	fmt.Printf("%q\n", WrapError(errors.New("failed"), "select 1", false).(*errs.Error).Data().(*Error).String())
+	// Output:
+	// failed
+	// select 1
+	// ""
+}
+
+func ExampleError_Error() {
+	err := WrapError(errors.New("failed"), "select 1", true)
+	fmt.Println(err.(*errs.Error).Data().(error))
+	fmt.Println(WrapError(errors.New("failed"), "select 1", false).(*errs.Error).Data().(error))
+	// Output:
+	// failed
+	// select 1
+	// failed
+}
+
+func ExampleError_MarshalJSON() {
+	sql := "select " + V(Secret("p'wd"))
+	err := run(runOptions{putSqlInError: true}, sql, []interface{}{"jack"},
+		func() (time.Time, error) { return time.Time{}, errors.New("failed") },
+	)
+	e := err.(*errs.Error).Data().(*Error)
+	e.Elapsed, e.Caller = time.Second, "main.go:10"
+	b, _ := json.Marshal(e)
+	fmt.Println(string(b))
+	// Output:
+	// {"message":"failed","sql":"select /*secret*/'***'","args":["\"jack\""],"elapsed":"1s","caller":"main.go:10"}
+}
@@ -0,0 +1,300 @@
+package bsql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lovego/errs"
+)
+
+type ExplainOptions struct {
+	// Analyze execute the statement to get actual rows and timing.
+	// The statement is always rolled back, so writes are explained safely.
+	Analyze bool
+	// Buffers report buffers usage. It requires Analyze before PostgreSQL 13,
+	// which report the planning buffers usage without Analyze.
+	Buffers bool
+	Verbose bool
+}
+
+func (opts ExplainOptions) sql(sql string) string {
+	options := []string{"FORMAT JSON"}
+	if opts.Analyze {
+		options = append(options, "ANALYZE")
+	}
+	if opts.Buffers {
+		options = append(options, "BUFFERS")
+	}
+	if opts.Verbose {
+		options = append(options, "VERBOSE")
+	}
+	return "EXPLAIN (" + strings.Join(options, ", ") + ") " + sql
+}
+
+// ExplainResult is the result of "EXPLAIN (FORMAT JSON)".
+type ExplainResult struct {
+	Plan          *Plan   `json:"Plan"`
+	PlanningTime  float64 `json:"Planning Time"`  // milliseconds
+	ExecutionTime float64 `json:"Execution Time"` // milliseconds
+}
+
+// Plan is a node of the plan tree.
+type Plan struct {
+	NodeType     string `json:"Node Type"`
+	RelationName string `json:"Relation Name"`
+	Schema       string `json:"Schema"`
+	Alias        string `json:"Alias"`
+	IndexName    string `json:"Index Name"`
+	JoinType     string `json:"Join Type"`
+	Filter       string `json:"Filter"`
+	IndexCond    string `json:"Index Cond"`
+
+	StartupCost float64 `json:"Startup Cost"`
+	TotalCost   float64 `json:"Total Cost"`
+	PlanRows    float64 `json:"Plan Rows"` // estimated rows per loop
+	PlanWidth   int     `json:"Plan Width"`
+
+	ActualStartupTime   float64 `json:"Actual Startup Time"` // milliseconds
+	ActualTotalTime     float64 `json:"Actual Total Time"`   // milliseconds
+	ActualRows          float64 `json:"Actual Rows"`         // actual rows per loop
+	ActualLoops         float64 `json:"Actual Loops"`
+	RowsRemovedByFilter float64 `json:"Rows Removed by Filter"`
+
+	SharedHitBlocks     int64 `json:"Shared Hit Blocks"`
+	SharedReadBlocks    int64 `json:"Shared Read Blocks"`
+	SharedDirtiedBlocks int64 `json:"Shared Dirtied Blocks"`
+	SharedWrittenBlocks int64 `json:"Shared Written Blocks"`
+	TempReadBlocks      int64 `json:"Temp Read Blocks"`
+	TempWrittenBlocks   int64 `json:"Temp Written Blocks"`
+
+	Plans []*Plan `json:"Plans"`
+}
+
+// Explain explain sql, see ExplainOptions for the options.
+func (db *DB) Explain(
+	ctx context.Context, opts ExplainOptions, sql string, args ...interface{},
+) (*ExplainResult, error) {
+	if !opts.Analyze {
+		return explain(ctx, db.Timeout, db.query, opts, sql, args)
+	}
+	var result *ExplainResult
+	err := db.RunInTransactionCtx(ctx, "explain", func(tx *Tx, ctx context.Context) (err error) {
+		if result, err = tx.Explain(ctx, opts, sql, args...); err != nil {
+			return err
+		}
+		return errExplainRollback
+	})
+	if err != nil && err != errExplainRollback {
+		return nil, err
+	}
+	return result, nil
+}
+
+var errExplainRollback = errors.New("bsql: rollback explain analyze")
+
+// Explain explain sql, see ExplainOptions for the options.
+// If opts.Analyze is true, the statement is rolled back to a savepoint,
+// so the rest of the transaction is not affected.
+func (tx *Tx) Explain(
+	ctx context.Context, opts ExplainOptions, sql string, args ...interface{},
+) (*ExplainResult, error) {
+	if !opts.Analyze {
+		return explain(ctx, tx.Timeout, tx.query, opts, sql, args)
+	}
+	if _, err := tx.ExecCtx(ctx, "explain savepoint", "SAVEPOINT bsql_explain"); err != nil {
+		return nil, err
+	}
+	result, err := explain(ctx, tx.Timeout, tx.query, opts, sql, args)
+	if _, rollbackErr := tx.ExecCtx(
+		ctx, "explain rollback", "ROLLBACK TO SAVEPOINT bsql_explain",
+	); rollbackErr != nil && err == nil {
+		err = rollbackErr
+	}
+	return result, err
+}
+
+func explain(
+	ctx context.Context, timeout time.Duration,
+	query func(context.Context, interface{}, string, []interface{}, ...bool) error,
+	opts ExplainOptions, sql string, args []interface{},
+) (*ExplainResult, error) {
+	if ctx.Done() == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if opts.Buffers && !opts.Analyze {
+		var version int
+		if err := query(ctx, &version, "SELECT current_setting('server_version_num')::int", nil); err != nil {
+			return nil, err
+		}
+		if version < 130000 {
+			return nil, errors.New("bsql: explain Buffers requires Analyze before PostgreSQL 13")
+		}
+	}
+	var results explainResults
+	if err := query(ctx, &results, opts.sql(sql), args); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 || results[0].Plan == nil {
+		return nil, errors.New("bsql: empty explain result")
+	}
+	return &results[0], nil
+}
+
+// explainResults scan the json column of "EXPLAIN (FORMAT JSON)".
+type explainResults []ExplainResult
+
+func (results *explainResults) Scan(src interface{}) error {
+	var plan []byte
+	switch v := src.(type) {
+	case []byte:
+		plan = v
+	case string:
+		plan = []byte(v)
+	default:
+		return fmt.Errorf("bsql: unexpected explain result: %T(%v)", src, src)
+	}
+	return errs.Trace(json.Unmarshal(plan, (*[]ExplainResult)(results)))
+}
+
+// Walk call fn on the node and all its descendants in depth first order.
+func (p *Plan) Walk(fn func(node *Plan, depth int)) {
+	p.walk(fn, 0)
+}
+
+func (p *Plan) walk(fn func(node *Plan, depth int), depth int) {
+	fn(p, depth)
+	for _, child := range p.Plans {
+		child.walk(fn, depth+1)
+	}
+}
+
+// SeqScansOnLargeTables return the "Seq Scan" nodes scanning no less than minRows rows.
+// The scanned rows are the actual rows plus rows removed by filter if analyzed,
+// otherwise the estimated rows.
+func (p *Plan) SeqScansOnLargeTables(minRows float64) (result []*Plan) {
+	p.Walk(func(node *Plan, depth int) {
+		if node.NodeType != "Seq Scan" {
+			return
+		}
+		rows := node.PlanRows
+		if node.ActualLoops > 0 {
+			rows = node.ActualRows + node.RowsRemovedByFilter/node.ActualLoops
+		}
+		if rows >= minRows {
+			result = append(result, node)
+		}
+	})
+	return
+}
+
+// MisestimatedNodes return the analyzed nodes whose actual rows and estimated rows
+// differ by more than factor times.
+func (p *Plan) MisestimatedNodes(factor float64) (result []*Plan) {
+	p.Walk(func(node *Plan, depth int) {
+		if node.Misestimation() > factor {
+			result = append(result, node)
+		}
+	})
+	return
+}
+
+// Misestimation return how many times the actual rows and estimated rows differ,
+// it's 0 if the node is not analyzed.
+func (p *Plan) Misestimation() float64 {
+	if p.ActualLoops == 0 {
+		return 0
+	}
+	big, small := p.ActualRows, p.PlanRows
+	if big < small {
+		big, small = small, big
+	}
+	if small < 1 {
+		small = 1
+	}
+	return big / small
+}
+
+// String render the plan tree as indented text, like the text format of EXPLAIN.
+func (r *ExplainResult) String() string {
+	var b strings.Builder
+	r.Plan.Walk(func(node *Plan, depth int) {
+		indent := strings.Repeat("      ", depth)
+		if depth > 0 {
+			b.WriteString(indent[:len(indent)-4] + "->  ")
+		}
+		b.WriteString(color.GreenString(node.title()) + "  " + color.BlueString(node.costs()) + "\n")
+		for _, detail := range node.details() {
+			b.WriteString(indent + "  " + detail + "\n")
+		}
+	})
+	if r.PlanningTime > 0 {
+		b.WriteString(fmt.Sprintf("Planning Time: %.3f ms\n", r.PlanningTime))
+	}
+	if r.ExecutionTime > 0 {
+		b.WriteString(fmt.Sprintf("Execution Time: %.3f ms\n", r.ExecutionTime))
+	}
+	return b.String()
+}
+
+func (p *Plan) title() string {
+	title := p.NodeType
+	if p.JoinType != "" && strings.HasSuffix(title, "Join") {
+		title = p.JoinType + " " + title
+	}
+	if p.IndexName != "" {
+		title += " using " + p.IndexName
+	}
+	if p.RelationName != "" {
+		title += " on " + p.RelationName
+		if p.Alias != "" && p.Alias != p.RelationName {
+			title += " " + p.Alias
+		}
+	}
+	return title
+}
+
+func (p *Plan) costs() string {
+	costs := fmt.Sprintf("(cost=%.2f..%.2f rows=%.0f width=%d)",
+		p.StartupCost, p.TotalCost, p.PlanRows, p.PlanWidth)
+	if p.ActualLoops > 0 {
+		costs += fmt.Sprintf(" (actual time=%.3f..%.3f rows=%.0f loops=%.0f)",
+			p.ActualStartupTime, p.ActualTotalTime, p.ActualRows, p.ActualLoops)
+	}
+	return costs
+}
+
+func (p *Plan) details() (result []string) {
+	if p.IndexCond != "" {
+		result = append(result, "Index Cond: "+p.IndexCond)
+	}
+	if p.Filter != "" {
+		result = append(result, "Filter: "+p.Filter)
+	}
+	if p.RowsRemovedByFilter > 0 {
+		result = append(result, fmt.Sprintf("Rows Removed by Filter: %.0f", p.RowsRemovedByFilter))
+	}
+	var buffers []string
+	for _, b := range []struct {
+		name   string
+		blocks int64
+	}{
+		{"shared hit", p.SharedHitBlocks}, {"shared read", p.SharedReadBlocks},
+		{"shared dirtied", p.SharedDirtiedBlocks}, {"shared written", p.SharedWrittenBlocks},
+		{"temp read", p.TempReadBlocks}, {"temp written", p.TempWrittenBlocks},
+	} {
+		if b.blocks > 0 {
+			buffers = append(buffers, fmt.Sprintf("%s=%d", b.name, b.blocks))
+		}
+	}
+	if len(buffers) > 0 {
+		result = append(result, "Buffers: "+strings.Join(buffers, " "))
+	}
+	return
+}
@@ -0,0 +1,150 @@
+package bsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+const testExplainJson = `[{
+  "Plan": {
+    "Node Type": "Hash Join", "Join Type": "Inner",
+    "Startup Cost": 1.07, "Total Cost": 25.61, "Plan Rows": 3, "Plan Width": 104,
+    "Actual Startup Time": 0.030, "Actual Total Time": 0.120, "Actual Rows": 3000, "Actual Loops": 1,
+    "Shared Hit Blocks": 12, "Shared Read Blocks": 3,
+    "Plans": [{
+      "Node Type": "Seq Scan", "Relation Name": "students", "Alias": "s",
+      "Startup Cost": 0.00, "Total Cost": 18.50, "Plan Rows": 850, "Plan Width": 72,
+      "Actual Startup Time": 0.010, "Actual Total Time": 0.050, "Actual Rows": 20000, "Actual Loops": 1,
+      "Filter": "(status = 1)", "Rows Removed by Filter": 5000
+    }, {
+      "Node Type": "Index Scan", "Relation Name": "classes", "Alias": "classes",
+      "Index Name": "classes_pkey", "Index Cond": "(id = s.class_id)",
+      "Startup Cost": 0.15, "Total Cost": 1.05, "Plan Rows": 1, "Plan Width": 32,
+      "Actual Startup Time": 0.001, "Actual Total Time": 0.001, "Actual Rows": 1, "Actual Loops": 1
+    }]
+  },
+  "Planning Time": 0.123,
+  "Execution Time": 0.456
+}]`
+
+func getTestExplainResult() *ExplainResult {
+	var results []ExplainResult
+	if err := json.Unmarshal([]byte(testExplainJson), &results); err != nil {
+		panic(err)
+	}
+	return &results[0]
+}
+
+func ExampleExplainResult_String() {
+	color.NoColor = true
+	fmt.Print(getTestExplainResult())
+	// Output:
+	// Inner Hash Join  (cost=1.07..25.61 rows=3 width=104) (actual time=0.030..0.120 rows=3000 loops=1)
+	//   Buffers: shared hit=12 shared read=3
+	//   ->  Seq Scan on students s  (cost=0.00..18.50 rows=850 width=72) (actual time=0.010..0.050 rows=20000 loops=1)
+	//         Filter: (status = 1)
+	//         Rows Removed by Filter: 5000
+	//   ->  Index Scan using classes_pkey on classes  (cost=0.15..1.05 rows=1 width=32) (actual time=0.001..0.001 rows=1 loops=1)
+	//         Index Cond: (id = s.class_id)
+	// Planning Time: 0.123 ms
+	// Execution Time: 0.456 ms
+}
+
+func ExamplePlan_SeqScansOnLargeTables() {
+	plan := getTestExplainResult().Plan
+	for _, node := range plan.SeqScansOnLargeTables(10000) {
+		fmt.Println(node.RelationName)
+	}
+	fmt.Println(len(plan.SeqScansOnLargeTables(100000)))
+	// Output:
+	// students
+	// 0
+}
+
+func ExamplePlan_MisestimatedNodes() {
+	for _, node := range getTestExplainResult().Plan.MisestimatedNodes(10) {
+		fmt.Println(node.NodeType, node.Misestimation())
+	}
+	// Output:
+	// Hash Join 1000
+	// Seq Scan 23.529411764705884
+}
+
+func ExampleExplainOptions() {
+	fmt.Println(ExplainOptions{}.sql("select 1"))
+	fmt.Println(ExplainOptions{Analyze: true, Buffers: true}.sql("select 1"))
+	// Output:
+	// EXPLAIN (FORMAT JSON) select 1
+	// EXPLAIN (FORMAT JSON, ANALYZE, BUFFERS) select 1
+}
+
+// explainHandler serve "EXPLAIN (FORMAT JSON)" as a json column like PostgreSQL of version.
+type explainHandler struct {
+	version int64
+}
+
+func (h explainHandler) Query(query string, args []interface{}) (*memdriver.Rows, error) {
+	if strings.Contains(query, "server_version_num") {
+		return &memdriver.Rows{
+			Columns: []string{"current_setting"}, Types: []string{"INT4"}, Values: [][]interface{}{{h.version}},
+		}, nil
+	}
+	return &memdriver.Rows{
+		Columns: []string{"QUERY PLAN"}, Types: []string{"JSON"}, Values: [][]interface{}{{[]byte(testExplainJson)}},
+	}, nil
+}
+
+func (explainHandler) Exec(query string, args []interface{}) (driver.Result, error) {
+	return nil, errors.New("unexpected exec: " + query)
+}
+
+func (explainHandler) Begin() error    { return nil }
+func (explainHandler) Commit() error   { return nil }
+func (explainHandler) Rollback() error { return nil }
+
+func ExampleDB_Explain() {
+	db := New(memdriver.Open(explainHandler{version: 120000}), time.Minute)
+	result, err := db.Explain(context.Background(), ExplainOptions{}, "select * from students")
+	fmt.Println(result.Plan.NodeType, len(result.Plan.Plans), result.PlanningTime, err)
+
+	_, err = db.Explain(context.Background(), ExplainOptions{Buffers: true}, "select * from students")
+	fmt.Println(err)
+
+	db = New(memdriver.Open(explainHandler{version: 130000}), time.Minute)
+	result, err = db.Explain(context.Background(), ExplainOptions{Buffers: true}, "select * from students")
+	fmt.Println(result.Plan.NodeType, err)
+	// Output:
+	// Hash Join 2 0.123 <nil>
+	// bsql: explain Buffers requires Analyze before PostgreSQL 13
+	// Hash Join <nil>
+}
+
+func TestExplain(t *testing.T) {
+	db := getTestDB()
+	createTable(t, db)
+	result, err := db.Explain(context.Background(), ExplainOptions{Analyze: true, Buffers: true},
+		`insert into students (name) values ($1)`, "jack",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Plan.NodeType != "ModifyTable" || result.Plan.ActualLoops != 1 {
+		t.Errorf("unexpected plan: %s", result)
+	}
+	var count int
+	if err := db.Query(&count, `select count(*) from students`); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("explain analyze not rolled back: %d", count)
+	}
+}
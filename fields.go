@@ -2,17 +2,22 @@ package bsql
 
 import (
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 
-	"github.com/lovego/strs"
+	"github.com/lovego/bsql/scan"
 	"github.com/lovego/struct_tag"
 	"github.com/lovego/structs"
 )
 
+// Field2Column convert a field path like "A.B" to the column name by the global NameMapper.
+// Use Field2ColumnOf to honour the column names given by the struct tags.
 func Field2Column(field string) string {
+	mapper := scan.GetNameMapper()
 	var path []string
 	for _, name := range strings.Split(field, ".") {
-		path = append(path, strs.CamelToSnake(name))
+		path = append(path, mapper.Field2Column(name))
 	}
 	return strings.Join(path, ".")
 }
@@ -28,6 +33,61 @@ func Fields2ColumnsStr(fields []string) string {
 	return strings.Join(Fields2Columns(fields), ",")
 }
 
+// Field2ColumnOf is like Field2Column, but the column names given by the tags of the fields of strct
+// are used, see scan.ColumnName. strct can be a struct, a struct pointer or a struct slice.
+func Field2ColumnOf(strct interface{}, field string) string {
+	return fieldColumn(structTypeOf(reflect.TypeOf(strct)), field)
+}
+
+func Fields2ColumnsOf(strct interface{}, fields []string) (result []string) {
+	typ := structTypeOf(reflect.TypeOf(strct))
+	for _, field := range fields {
+		result = append(result, fieldColumn(typ, field))
+	}
+	return
+}
+
+func Fields2ColumnsStrOf(strct interface{}, fields []string) string {
+	return strings.Join(Fields2ColumnsOf(strct, fields), ",")
+}
+
+// fieldColumn return the column name of a field path like "A.B" of typ.
+func fieldColumn(typ reflect.Type, fieldPath string) string {
+	return fieldColumnWithMapper(typ, fieldPath, scan.GetNameMapper())
+}
+
+func fieldColumnWithMapper(typ reflect.Type, fieldPath string, mapper NameMapper) string {
+	var path []string
+	for _, name := range strings.Split(fieldPath, ".") {
+		typ = structTypeOf(typ)
+		if typ != nil && typ.Kind() == reflect.Struct {
+			if field, ok := typ.FieldByName(name); ok {
+				path = append(path, scan.ColumnNameWithMapper(field, mapper))
+				typ = field.Type
+				continue
+			}
+		}
+		path = append(path, mapper.Field2Column(name))
+		typ = nil
+	}
+	return strings.Join(path, ".")
+}
+
+// structTypeOf return the struct type of a struct pointer, slice, array or map keys.
+func structTypeOf(typ reflect.Type) reflect.Type {
+	for typ != nil {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+		case reflect.Map:
+			typ = typ.Key()
+		default:
+			return typ
+		}
+	}
+	return nil
+}
+
 func FieldsToColumns(fields []string, prefix string, exclude []string) (result []string) {
 	for _, field := range fields {
 		if notIn(field, exclude) {
@@ -64,3 +124,121 @@ func notIn(target string, slice []string) bool {
 	}
 	return true
 }
+
+// sqlTag is the parsed `sql` tag. It's made of the column definition and options,
+// separated by ";", eg. `sql:"varchar(50) default 'x'; secret"`.
+// The "secret" option redact the field value from debug output, errors and tracer.
+// The "default" option render the zero field value as DEFAULT in StructValues,
+// the default expression can be given as "default:now()" if not in the column definition.
+// A default clause in the column definition only gives the expression, the zero value is written without the option.
+// The "name" option give the column name, like "name:userID", which can also be given by the `column` tag.
+// The "readonly" option keep the field from being updated by UpdateSql and BulkUpdateSql.
+type sqlTag struct {
+	Def     string // column definition
+	Options map[string]string
+}
+
+var sqlTagOptions = map[string]bool{"secret": true, "default": true, "readonly": true, "name": true}
+
+func parseSqlTag(tag reflect.StructTag) sqlTag {
+	var result sqlTag
+	value, _ := struct_tag.Lookup(string(tag), `sql`)
+	var defs []string
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		name, arg := part, ""
+		if i := strings.IndexByte(part, ':'); i > 0 {
+			name, arg = part[:i], strings.TrimSpace(part[i+1:])
+		}
+		if sqlTagOptions[name] {
+			if result.Options == nil {
+				result.Options = make(map[string]string)
+			}
+			result.Options[name] = arg
+		} else if part != "" {
+			defs = append(defs, part)
+		}
+	}
+	result.Def = strings.Join(defs, " ")
+	return result
+}
+
+func (tag sqlTag) Has(option string) bool {
+	_, ok := tag.Options[option]
+	return ok
+}
+
+var defaultClauseRegexp = regexp.MustCompile(`(?i)\bdefault\s+(.+?)\s*(?:\b(?:not\s+null|null|` +
+	`primary\s+key|unique|check|references|constraint|generated|collate)\b|$)`)
+
+// HasDefault report whether the zero field should take the column default, which is opted in by the
+// "default" option. A default clause in the column definition only gives the expression, see DefaultExpr.
+func (tag sqlTag) HasDefault() bool {
+	return tag.Has("default")
+}
+
+// ColumnType return the column type given by the column definition, empty if not given.
+func (tag sqlTag) ColumnType() string {
+	if hasColumnType(tag.Def) {
+		return columnTypeRegexp.FindString(tag.Def)
+	}
+	return ""
+}
+
+// DefaultExpr return the column default expression given by the "default" option
+// or the column definition, empty if not given.
+func (tag sqlTag) DefaultExpr() string {
+	if expr := tag.Options["default"]; expr != "" {
+		return expr
+	}
+	if m := defaultClauseRegexp.FindStringSubmatch(tag.Def); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+type fieldTagKey struct {
+	typ       reflect.Type
+	fieldPath string
+}
+
+type fieldTagResult struct {
+	tag sqlTag
+	ok  bool
+}
+
+var fieldTags sync.Map // fieldTagKey => fieldTagResult
+
+// fieldTag return the parsed `sql` tag of the field indicated by a field path like "A.B".
+// The parsed tags are cached, because it's called for every field of every row.
+func fieldTag(typ reflect.Type, fieldPath string) (sqlTag, bool) {
+	key := fieldTagKey{typ, fieldPath}
+	if v, ok := fieldTags.Load(key); ok {
+		result := v.(fieldTagResult)
+		return result.tag, result.ok
+	}
+	tag, ok := lookupFieldTag(typ, fieldPath)
+	fieldTags.Store(key, fieldTagResult{tag, ok})
+	return tag, ok
+}
+
+func lookupFieldTag(typ reflect.Type, fieldPath string) (sqlTag, bool) {
+	var field reflect.StructField
+	for _, name := range strings.Split(fieldPath, ".") {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+			if typ.Kind() == reflect.Interface {
+				return sqlTag{}, false
+			}
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return sqlTag{}, false
+		}
+		var ok bool
+		if field, ok = typ.FieldByName(name); !ok {
+			return sqlTag{}, false
+		}
+		typ = field.Type
+	}
+	return parseSqlTag(field.Tag), true
+}
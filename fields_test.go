@@ -61,3 +61,27 @@ func ExampleFieldsFromStruct() {
 	// Output:
 	// [Name T3Name TestT4]
 }
+
+func ExampleField2ColumnOf() {
+	type user struct {
+		Id      int64  `column:"userID"`
+		URL     string `sql:"varchar(100); name:url"`
+		Profile struct {
+			HomePage string `column:"home"`
+		}
+		CreatedAt string
+	}
+	fmt.Println(Field2ColumnOf(user{}, "Id"), Field2ColumnOf([]*user{}, "URL"))
+	fmt.Println(Fields2ColumnsOf(&user{}, []string{"Profile.HomePage", "CreatedAt", "Unknown"}))
+	fmt.Println(ColumnsStrFromStruct(user{}, []string{"Profile"}))
+	fmt.Println(ColumnsDefs(struct {
+		URL  string `sql:"varchar(100); name:url"`
+		Name string `column:"Name"`
+	}{}))
+	// Output:
+	// userID url
+	// [profile.home created_at unknown]
+	// userID,url,created_at
+	// url varchar(100) NOT NULL,
+	// Name text NOT NULL
+}
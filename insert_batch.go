@@ -0,0 +1,164 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// InsertBatchOptions control how InsertBatch splits and runs the chunks.
+type InsertBatchOptions struct {
+	MaxRows  int // max rows of a chunk, default 1000.
+	MaxBytes int // max bytes of the sql of a chunk, default 4MB.
+	// Placeholders send the values as parameters "$n" instead of inlining literals,
+	// then chunks are also bounded by MaxParams.
+	Placeholders bool
+	MaxParams    int // max parameters of a chunk, default and at most MaxArgs.
+	// OnConflict is appended to every INSERT, eg. "ON CONFLICT (id) DO NOTHING".
+	// The output of UpsertSql is accepted too, of which the ON CONFLICT clause is used.
+	OnConflict string
+	// SingleTx run all the chunks in one transaction, otherwise each chunk commits by itself.
+	// Tx.InsertBatch ignores it and always runs in the Tx.
+	SingleTx bool
+	Progress func(InsertBatchProgress) // called after each chunk is inserted.
+}
+
+// InsertBatchProgress is reported after each chunk is inserted.
+type InsertBatchProgress struct {
+	Chunk        int   // the sequence number of the chunk, begins at 1.
+	Rows         int   // rows of the chunk.
+	InsertedRows int   // rows inserted, including this chunk.
+	TotalRows    int   // rows of all chunks.
+	RowsAffected int64 // rows affected by the chunk.
+}
+
+// InsertBatch insert the fields of a struct slice into table in chunks, return the total rows affected.
+// Chunks are bounded by row count, sql bytes and parameter count, see InsertBatchOptions.
+func (db *DB) InsertBatch(
+	ctx context.Context, table string, fields []string, data interface{}, opts InsertBatchOptions,
+) (int64, error) {
+	if !opts.SingleTx {
+		return insertBatch(table, fields, data, opts, func(sql string, args []interface{}) (int64, error) {
+			return execRowsAffected(db.ExecCtx(ctx, "InsertBatch", sql, args...))
+		})
+	}
+	var total int64
+	err := db.RunInTransactionCtx(ctx, "InsertBatch", func(tx *Tx, ctx context.Context) error {
+		var err error
+		total, err = tx.InsertBatch(ctx, table, fields, data, opts)
+		return err
+	})
+	return total, err
+}
+
+// InsertBatch is like DB.InsertBatch, but runs all the chunks in the Tx.
+func (tx *Tx) InsertBatch(
+	ctx context.Context, table string, fields []string, data interface{}, opts InsertBatchOptions,
+) (int64, error) {
+	return insertBatch(table, fields, data, opts, func(sql string, args []interface{}) (int64, error) {
+		return execRowsAffected(tx.ExecCtx(ctx, "InsertBatch", sql, args...))
+	})
+}
+
+func insertBatch(
+	table string, fields []string, data interface{}, opts InsertBatchOptions,
+	exec func(sql string, args []interface{}) (int64, error),
+) (int64, error) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		log.Panic("bsql: InsertBatch data must be a slice or array.")
+	}
+	opts.setDefaults()
+	prefix := "INSERT INTO " + table + " (" + Fields2ColumnsStrOf(data, fields) + ")\nVALUES "
+	suffix := onConflictClause(opts.OnConflict)
+
+	var args Args
+	render := V
+	if opts.Placeholders {
+		render = args.V
+	}
+	var rows []string
+	var size, inserted int
+	var chunk int
+	var total int64
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := args.Err(); err != nil {
+			return err
+		}
+		affected, err := exec(prefix+strings.Join(rows, ",")+suffix, args.List())
+		if err != nil {
+			return err
+		}
+		chunk++
+		inserted += len(rows)
+		total += affected
+		if opts.Progress != nil {
+			opts.Progress(InsertBatchProgress{
+				Chunk: chunk, Rows: len(rows), InsertedRows: inserted, TotalRows: value.Len(),
+				RowsAffected: affected,
+			})
+		}
+		rows, size, args = rows[:0], len(prefix)+len(suffix), Args{}
+		return nil
+	}
+	size = len(prefix) + len(suffix)
+
+	for i := 0; i < value.Len(); i++ {
+		if len(rows) >= opts.MaxRows || opts.Placeholders && args.Len()+len(fields) > opts.MaxParams {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+		mark := args.Len()
+		row := "(" + structFields(value.Index(i), fields, render, false) + ")"
+		if len(rows) > 0 && size+len(row)+1 > opts.MaxBytes {
+			// the placeholders of the row must be renumbered in the next chunk.
+			args.list = args.list[:mark]
+			if err := flush(); err != nil {
+				return total, err
+			}
+			row = "(" + structFields(value.Index(i), fields, render, false) + ")"
+		}
+		rows = append(rows, row)
+		size += len(row) + 1
+	}
+	return total, flush()
+}
+
+func (opts *InsertBatchOptions) setDefaults() {
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = 1000
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = 4 << 20
+	}
+	if opts.MaxParams <= 0 || opts.MaxParams > MaxArgs {
+		opts.MaxParams = MaxArgs
+	}
+}
+
+var onConflictRegexp = regexp.MustCompile(`(?i)\bon\s+conflict\b`)
+
+// onConflictClause return the ON CONFLICT clause of s, which may be the output of UpsertSql.
+func onConflictClause(s string) string {
+	if s == "" {
+		return ""
+	}
+	if loc := onConflictRegexp.FindStringIndex(s); loc != nil {
+		s = s[loc[0]:]
+	}
+	return "\n" + s
+}
+
+func execRowsAffected(result sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
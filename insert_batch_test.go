@@ -0,0 +1,130 @@
+package bsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+// execRecorder print the statements run on it.
+type execRecorder struct{}
+
+func (execRecorder) Query(query string, args []interface{}) (*memdriver.Rows, error) {
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+func (execRecorder) Exec(query string, args []interface{}) (driver.Result, error) {
+	fmt.Println(query, args)
+	return driver.RowsAffected(int64(strings.Count(query, "),(") + 1)), nil
+}
+
+func (execRecorder) Begin() error    { fmt.Println("BEGIN"); return nil }
+func (execRecorder) Commit() error   { fmt.Println("COMMIT"); return nil }
+func (execRecorder) Rollback() error { fmt.Println("ROLLBACK"); return nil }
+
+func ExampleDB_InsertBatch() {
+	type user struct {
+		Id   int
+		Name string
+	}
+	users := []user{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+	db := New(memdriver.Open(execRecorder{}), time.Minute)
+
+	total, err := db.InsertBatch(context.Background(), "users", []string{"Id", "Name"}, users,
+		InsertBatchOptions{
+			MaxRows:    2,
+			OnConflict: UpsertSql("users", []string{"Id", "Name"}, []string{"Id"}, nil),
+			Progress: func(p InsertBatchProgress) {
+				fmt.Printf("%+v\n", p)
+			},
+		},
+	)
+	fmt.Println(total, err)
+	// Output:
+	// INSERT INTO users (id,name)
+	// VALUES (1,'a'),(2,'b')
+	// ON CONFLICT (id) DO UPDATE SET
+	// (         name) =
+	// (excluded.name) []
+	// {Chunk:1 Rows:2 InsertedRows:2 TotalRows:5 RowsAffected:2}
+	// INSERT INTO users (id,name)
+	// VALUES (3,'c'),(4,'d')
+	// ON CONFLICT (id) DO UPDATE SET
+	// (         name) =
+	// (excluded.name) []
+	// {Chunk:2 Rows:2 InsertedRows:4 TotalRows:5 RowsAffected:2}
+	// INSERT INTO users (id,name)
+	// VALUES (5,'e')
+	// ON CONFLICT (id) DO UPDATE SET
+	// (         name) =
+	// (excluded.name) []
+	// {Chunk:3 Rows:1 InsertedRows:5 TotalRows:5 RowsAffected:1}
+	// 5 <nil>
+}
+
+func ExampleDB_InsertBatch_placeholders() {
+	type user struct {
+		Id   int
+		Name string
+	}
+	users := []user{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+	db := New(memdriver.Open(execRecorder{}), time.Minute)
+
+	total, err := db.InsertBatch(context.Background(), "users", []string{"Id", "Name"}, users,
+		InsertBatchOptions{
+			Placeholders: true, MaxParams: 5, SingleTx: true,
+			OnConflict: "ON CONFLICT (id) DO NOTHING",
+		},
+	)
+	fmt.Println(total, err)
+	// Output:
+	// BEGIN
+	// INSERT INTO users (id,name)
+	// VALUES ($1,$2),($3,$4)
+	// ON CONFLICT (id) DO NOTHING [1 a 2 b]
+	// INSERT INTO users (id,name)
+	// VALUES ($1,$2),($3,$4)
+	// ON CONFLICT (id) DO NOTHING [3 c 4 d]
+	// INSERT INTO users (id,name)
+	// VALUES ($1,$2)
+	// ON CONFLICT (id) DO NOTHING [5 e]
+	// COMMIT
+	// 5 <nil>
+}
+
+func ExampleTx_InsertBatch() {
+	users := []struct{ Name string }{{"a"}, {"b"}, {"c"}, {"it's"}}
+	db := New(memdriver.Open(execRecorder{}), time.Minute)
+
+	fmt.Println(db.RunInTransaction(func(tx *Tx) error {
+		// "INSERT INTO users (name)\nVALUES " is 32 bytes, "('a')," is 6 bytes.
+		_, err := tx.InsertBatch(context.Background(), "users", []string{"Name"}, users,
+			InsertBatchOptions{MaxBytes: 45},
+		)
+		return err
+	}))
+	// Output:
+	// BEGIN
+	// INSERT INTO users (name)
+	// VALUES ('a'),('b') []
+	// INSERT INTO users (name)
+	// VALUES ('c') []
+	// INSERT INTO users (name)
+	// VALUES ('it''s') []
+	// COMMIT
+	// <nil>
+}
+
+func Example_onConflictClause() {
+	fmt.Printf("%q\n", onConflictClause("on conflict (id) do nothing"))
+	fmt.Printf("%q\n", onConflictClause("insert into users (id) values (1)\non  Conflict (id) do nothing"))
+	fmt.Printf("%q\n", onConflictClause(""))
+	// Output:
+	// "\non conflict (id) do nothing"
+	// "\non  Conflict (id) do nothing"
+	// ""
+}
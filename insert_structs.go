@@ -0,0 +1,189 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lovego/bsql/scan"
+	"github.com/lovego/value"
+)
+
+// InsertStructsOptions of InsertStructs.
+type InsertStructsOptions struct {
+	Exclude []string // fields not to insert.
+	// Returning are the extra fields to return and write back,
+	// besides the generated Id and the fields with `sql:"default"` tag.
+	Returning []string
+}
+
+// InsertStructs insert the elements of the struct slice pointed by slicePtr into table,
+// and write the generated Id and the defaults of the `sql:"default"` fields back into the elements.
+// Ids are generated before inserting, by the serial sequence or the default expression of Id,
+// and associated with the ordinals of the elements, so the order of RETURNING rows doesn't matter.
+// Id is serial only if its column type is given as serial or identity by the sql tag, like `sql:"bigserial"`.
+// The zero `sql:"default"` fields are inserted as DEFAULT, so their column defaults apply.
+// db is expected to be a DB or Tx, whose NameMapper is used for the columns,
+// otherwise the global NameMapper is used and the statement without RETURNING isn't bound to ctx.
+func InsertStructs(
+	ctx context.Context, db DbOrTx, table string, slicePtr interface{}, opts InsertStructsOptions,
+) error {
+	slice := reflect.ValueOf(slicePtr)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		log.Panic("bsql: InsertStructs slicePtr must be a pointer to a struct slice.")
+	}
+	slice = slice.Elem()
+	structType := slice.Type().Elem()
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		log.Panic("bsql: InsertStructs slicePtr must be a pointer to a struct slice.")
+	}
+	if slice.Len() == 0 {
+		return nil
+	}
+
+	mapper := nameMapperOf(db)
+	columns := func(fields []string) (result []string) {
+		for _, field := range fields {
+			result = append(result, fieldColumnWithMapper(structType, field, mapper))
+		}
+		return
+	}
+
+	var idExpr string
+	var inserted, returning []string
+	traverseStructFields(structType, func(field reflect.StructField) {
+		if !notIn(field.Name, opts.Exclude) {
+			return
+		}
+		tag := parseSqlTag(field.Tag)
+		switch {
+		case field.Name == "Id" && isSerial(field, tag):
+			idExpr = "nextval(pg_get_serial_sequence(" + Q(table) + ", " + Q(scan.ColumnNameWithMapper(field, mapper)) + "))"
+			returning = append(returning, field.Name)
+		case field.Name == "Id" && tag.HasDefault() && tag.DefaultExpr() != "":
+			idExpr = tag.DefaultExpr()
+			returning = append(returning, field.Name)
+		case tag.HasDefault():
+			inserted = append(inserted, field.Name)
+			returning = append(returning, field.Name)
+		default:
+			inserted = append(inserted, field.Name)
+			if !notIn(field.Name, opts.Returning) {
+				returning = append(returning, field.Name)
+			}
+		}
+	})
+
+	if len(returning) == 0 {
+		return execCtx(ctx, db, "InsertStructs", "INSERT INTO "+table+
+			" ("+strings.Join(columns(inserted), ",")+")\nVALUES "+StructValues(slice.Interface(), inserted))
+	}
+	if idExpr == "" {
+		return errors.New("bsql: InsertStructs needs a serial or default-tagged Id to write back " +
+			strings.Join(returning, ", "))
+	}
+
+	rows := reflect.New(reflect.SliceOf(returningType(structType, returning)))
+	if err := db.QueryCtx(ctx, "InsertStructs", rows.Interface(),
+		insertStructsSql(table, idExpr, fieldColumnWithMapper(structType, "Id", mapper),
+			slice, inserted, columns(inserted), columns(returning)),
+	); err != nil {
+		return err
+	}
+	rows = rows.Elem()
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		elem := slice.Index(int(row.Field(0).Int()) - 1)
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		for j, field := range returning {
+			// Settable allocate the nil embedded struct pointers the field is promoted through.
+			value.Settable(elem, []string{field}).Set(row.Field(j + 1))
+		}
+	}
+	return nil
+}
+
+func insertStructsSql(
+	table, idExpr, idColumn string, slice reflect.Value, inserted, insertedColumns, returningColumns []string,
+) string {
+	var values []string
+	for i := 0; i < slice.Len(); i++ {
+		row := "(SELECT ids[" + strconv.Itoa(i+1) + "] FROM bsql_ids)"
+		if len(inserted) > 0 {
+			row += "," + structFields(slice.Index(i), inserted, V, false)
+		}
+		values = append(values, "("+row+")")
+	}
+	// the ids are aggregated into an array indexed by the ordinals, so every row gets its id in constant time.
+	return `WITH bsql_ids AS (
+  SELECT array_agg(` + idExpr + ` ORDER BY bsql_ordinal) AS ids FROM generate_series(1, ` + strconv.Itoa(slice.Len()) + `) AS bsql_ordinal
+), bsql_inserted AS (
+  INSERT INTO ` + table + ` (` + strings.Join(append([]string{idColumn}, insertedColumns...), ",") + `)
+  VALUES ` + strings.Join(values, ",\n  ") + `
+  RETURNING ` + strings.Join(returningColumns, ",") + `
+)
+SELECT bsql_id.bsql_ordinal, bsql_inserted.*
+FROM bsql_ids CROSS JOIN unnest(bsql_ids.ids) WITH ORDINALITY AS bsql_id(id, bsql_ordinal)
+JOIN bsql_inserted ON bsql_inserted.` + idColumn + ` = bsql_id.id`
+}
+
+// returningType is the struct type to scan the RETURNING rows, with the ordinal as the first field.
+func returningType(structType reflect.Type, returning []string) reflect.Type {
+	fields := []reflect.StructField{{Name: "BsqlOrdinal", Type: reflect.TypeOf(0), Tag: `column:"bsql_ordinal"`}}
+	for _, name := range returning {
+		field, _ := structType.FieldByName(name)
+		// keep the tag for the column name given by it.
+		fields = append(fields, reflect.StructField{Name: name, Type: field.Type, Tag: field.Tag})
+	}
+	return reflect.StructOf(fields)
+}
+
+// isSerial report whether the integer Id field is a serial or identity column given by the sql tag.
+func isSerial(field reflect.StructField, tag sqlTag) bool {
+	switch field.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return false
+	}
+	def := strings.ToLower(tag.Def)
+	return strings.Contains(firstWordRegexp.FindString(def), "serial") || strings.Contains(def, "identity")
+}
+
+// nameMapperOf return the NameMapper of a DB or Tx, or the global one.
+func nameMapperOf(db DbOrTx) NameMapper {
+	var mapper NameMapper
+	switch db := db.(type) {
+	case *DB:
+		mapper = db.NameMapper
+	case *Tx:
+		mapper = db.NameMapper
+	}
+	if mapper == nil {
+		return scan.GetNameMapper()
+	}
+	return mapper
+}
+
+// ctxExecer is implemented by DB and Tx, but not required by DbOrTx to keep its implementations working.
+type ctxExecer interface {
+	ExecCtx(ctx context.Context, opName string, sql string, args ...interface{}) (sql.Result, error)
+}
+
+func execCtx(ctx context.Context, db DbOrTx, opName, sql string) error {
+	if execer, ok := db.(ctxExecer); ok {
+		_, err := execer.ExecCtx(ctx, opName, sql)
+		return err
+	}
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,130 @@
+package bsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/lovego/bsql/internal/memdriver"
+	"github.com/lovego/bsql/scan"
+)
+
+// returningRows print the query and return rows.
+type returningRows memdriver.Rows
+
+func (r *returningRows) Query(query string, args []interface{}) (*memdriver.Rows, error) {
+	fmt.Println(query)
+	return (*memdriver.Rows)(r), nil
+}
+
+func (r *returningRows) Exec(query string, args []interface{}) (driver.Result, error) {
+	fmt.Println(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (*returningRows) Begin() error    { return nil }
+func (*returningRows) Commit() error   { return nil }
+func (*returningRows) Rollback() error { return nil }
+
+func ExampleInsertStructs() {
+	type user struct {
+		Id        int64 `sql:"bigserial"`
+		Name      string
+		Status    string    `sql:"default 'active'; default"`
+		CreatedAt time.Time `sql:"default:now()"`
+	}
+	createdAt := time.Date(2019, 6, 19, 0, 0, 0, 0, time.UTC)
+	// the RETURNING rows are in a different order from the elements.
+	db := New(memdriver.Open(&returningRows{
+		Columns: []string{"bsql_ordinal", "id", "status", "created_at"},
+		Types:   []string{"INT8", "INT8", "TEXT", "TIMESTAMPTZ"},
+		Values: [][]interface{}{
+			{int64(2), int64(11), "banned", createdAt},
+			{int64(1), int64(10), "active", createdAt},
+		},
+	}), time.Minute)
+
+	users := []*user{{Name: "Lili"}, {Name: "Lucy", Status: "banned"}}
+	fmt.Println(InsertStructs(context.Background(), db, "users", &users, InsertStructsOptions{}))
+	for _, u := range users {
+		fmt.Printf("%+v\n", *u)
+	}
+	// Output:
+	// WITH bsql_ids AS (
+	//   SELECT array_agg(nextval(pg_get_serial_sequence('users', 'id')) ORDER BY bsql_ordinal) AS ids FROM generate_series(1, 2) AS bsql_ordinal
+	// ), bsql_inserted AS (
+	//   INSERT INTO users (id,name,status,created_at)
+	//   VALUES ((SELECT ids[1] FROM bsql_ids),'Lili',DEFAULT,DEFAULT),
+	//   ((SELECT ids[2] FROM bsql_ids),'Lucy','banned',DEFAULT)
+	//   RETURNING id,status,created_at
+	// )
+	// SELECT bsql_id.bsql_ordinal, bsql_inserted.*
+	// FROM bsql_ids CROSS JOIN unnest(bsql_ids.ids) WITH ORDINALITY AS bsql_id(id, bsql_ordinal)
+	// JOIN bsql_inserted ON bsql_inserted.id = bsql_id.id
+	// <nil>
+	// {Id:10 Name:Lili Status:active CreatedAt:2019-06-19 00:00:00 +0000 UTC}
+	// {Id:11 Name:Lucy Status:banned CreatedAt:2019-06-19 00:00:00 +0000 UTC}
+}
+
+func ExampleInsertStructs_noReturning() {
+	type tag struct {
+		Name string
+	}
+	db := New(memdriver.Open(&returningRows{}), time.Minute)
+	tags := []tag{{"a"}, {"b"}}
+	fmt.Println(InsertStructs(context.Background(), db, "tags", &tags, InsertStructsOptions{}))
+
+	type user struct {
+		Name      string
+		CreatedAt time.Time `sql:"default:now()"`
+	}
+	users := []user{{Name: "Lili"}}
+	fmt.Println(InsertStructs(context.Background(), db, "users", &users, InsertStructsOptions{}))
+
+	// an untagged Id may be a plain integer column, so it's not taken as serial.
+	type account struct {
+		Id        int64
+		CreatedAt time.Time `sql:"default:now()"`
+	}
+	accounts := []account{{Id: 1}}
+	fmt.Println(InsertStructs(context.Background(), db, "accounts", &accounts, InsertStructsOptions{}))
+	// Output:
+	// INSERT INTO tags (name)
+	// VALUES ('a'),('b')
+	// <nil>
+	// bsql: InsertStructs needs a serial or default-tagged Id to write back CreatedAt
+	// bsql: InsertStructs needs a serial or default-tagged Id to write back CreatedAt
+}
+
+func ExampleInsertStructs_nameMapper() {
+	type user struct {
+		Id        int64 `sql:"bigserial"`
+		Name      string
+		CreatedAt time.Time `sql:"default:now()"`
+	}
+	createdAt := time.Date(2019, 6, 19, 0, 0, 0, 0, time.UTC)
+	db := New(memdriver.Open(&returningRows{
+		Columns: []string{"bsql_ordinal", "usr_id", "usr_created_at"},
+		Types:   []string{"INT8", "INT8", "TIMESTAMPTZ"},
+		Values:  [][]interface{}{{int64(1), int64(10), createdAt}},
+	}), time.Minute)
+	db.NameMapper = scan.PrefixMapper{Prefix: "usr_"}
+
+	users := []user{{Name: "Lili"}}
+	fmt.Println(InsertStructs(context.Background(), db, "users", &users, InsertStructsOptions{}))
+	fmt.Printf("%+v\n", users[0])
+	// Output:
+	// WITH bsql_ids AS (
+	//   SELECT array_agg(nextval(pg_get_serial_sequence('users', 'usr_id')) ORDER BY bsql_ordinal) AS ids FROM generate_series(1, 1) AS bsql_ordinal
+	// ), bsql_inserted AS (
+	//   INSERT INTO users (usr_id,usr_name,usr_created_at)
+	//   VALUES ((SELECT ids[1] FROM bsql_ids),'Lili',DEFAULT)
+	//   RETURNING usr_id,usr_created_at
+	// )
+	// SELECT bsql_id.bsql_ordinal, bsql_inserted.*
+	// FROM bsql_ids CROSS JOIN unnest(bsql_ids.ids) WITH ORDINALITY AS bsql_id(id, bsql_ordinal)
+	// JOIN bsql_inserted ON bsql_inserted.usr_id = bsql_id.id
+	// <nil>
+	// {Id:10 Name:Lili CreatedAt:2019-06-19 00:00:00 +0000 UTC}
+}
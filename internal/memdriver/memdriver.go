@@ -0,0 +1,210 @@
+// Package memdriver is an in-memory database/sql driver serving results from a Handler,
+// so *sql.Rows can be made from canned or recorded rows.
+package memdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// Rows is a result in driver values.
+type Rows struct {
+	Columns []string
+	Types   []string // DatabaseTypeName of columns
+	Values  [][]interface{}
+}
+
+// Handler serves the statements and transaction controls run on the driver.
+// Args are passed as is, without converting to driver values.
+type Handler interface {
+	Query(query string, args []interface{}) (*Rows, error)
+	Exec(query string, args []interface{}) (driver.Result, error)
+	Begin() error
+	Commit() error
+	Rollback() error
+}
+
+// Open return a *sql.DB served by handler.
+func Open(handler Handler) *sql.DB {
+	return sql.OpenDB(connector{handler})
+}
+
+var rowsDB = Open(rowsHandler{})
+
+// Query return a *sql.Rows of rows.
+func Query(ctx context.Context, rows *Rows) (*sql.Rows, error) {
+	return rowsDB.QueryContext(ctx, "", rows)
+}
+
+// rowsHandler return the rows passed as the only arg.
+type rowsHandler struct{}
+
+func (rowsHandler) Query(_ string, args []interface{}) (*Rows, error) {
+	if len(args) == 1 {
+		if rows, ok := args[0].(*Rows); ok {
+			return rows, nil
+		}
+	}
+	return nil, errors.New("memdriver: rows required")
+}
+
+func (rowsHandler) Exec(string, []interface{}) (driver.Result, error) {
+	return nil, errors.New("memdriver: exec not supported")
+}
+
+func (rowsHandler) Begin() error    { return nil }
+func (rowsHandler) Commit() error   { return nil }
+func (rowsHandler) Rollback() error { return nil }
+
+type connector struct {
+	handler Handler
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn{c.handler}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return memDriver{c.handler}
+}
+
+type memDriver struct {
+	handler Handler
+}
+
+func (d memDriver) Open(string) (driver.Conn, error) {
+	return conn{d.handler}, nil
+}
+
+type conn struct {
+	handler Handler
+}
+
+func (c conn) Prepare(query string) (driver.Stmt, error) {
+	return stmt{c, query}, nil
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	if err := c.handler.Begin(); err != nil {
+		return nil, err
+	}
+	return tx{c.handler}, nil
+}
+
+// CheckNamedValue accept any args as is, so handlers can see the original args.
+func (c conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c conn) QueryContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Rows, error) {
+	data, err := c.handler.Query(query, namedValuesToArgs(args))
+	if err != nil {
+		return nil, err
+	}
+	return &rows{Rows: data}, nil
+}
+
+func (c conn) ExecContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Result, error) {
+	return c.handler.Exec(query, namedValuesToArgs(args))
+}
+
+func namedValuesToArgs(namedValues []driver.NamedValue) []interface{} {
+	if len(namedValues) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(namedValues))
+	for i := range namedValues {
+		args[i] = namedValues[i].Value
+	}
+	return args
+}
+
+type stmt struct {
+	conn  conn
+	query string
+}
+
+func (s stmt) Close() error {
+	return nil
+}
+
+func (s stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, valuesToNamedValues(args))
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, valuesToNamedValues(args))
+}
+
+func valuesToNamedValues(values []driver.Value) []driver.NamedValue {
+	namedValues := make([]driver.NamedValue, len(values))
+	for i := range values {
+		namedValues[i] = driver.NamedValue{Ordinal: i + 1, Value: values[i]}
+	}
+	return namedValues
+}
+
+type tx struct {
+	handler Handler
+}
+
+func (t tx) Commit() error {
+	return t.handler.Commit()
+}
+
+func (t tx) Rollback() error {
+	return t.handler.Rollback()
+}
+
+type rows struct {
+	*Rows
+	i int
+}
+
+func (r *rows) Columns() []string {
+	return r.Rows.Columns
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if index < len(r.Types) {
+		return r.Types[index]
+	}
+	return ""
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.Values) {
+		return io.EOF
+	}
+	if len(dest) != len(r.Values[r.i]) {
+		return errors.New("memdriver: columns count mismatch")
+	}
+	for i, v := range r.Values[r.i] {
+		dest[i] = v
+	}
+	r.i++
+	return nil
+}
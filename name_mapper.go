@@ -0,0 +1,13 @@
+package bsql
+
+import "github.com/lovego/bsql/scan"
+
+// NameMapper convert between struct field names and column names, see scan.NameMapper.
+// scan.SnakeMapper, scan.AcronymMapper, scan.PrefixMapper and scan.LowerMapper are provided.
+type NameMapper = scan.NameMapper
+
+// SetNameMapper set the global NameMapper used by Field2Column, Columns2Fields and scanning.
+// It should be called before any use, eg. in init. A nil mapper restores the default scan.SnakeMapper.
+func SetNameMapper(mapper NameMapper) {
+	scan.SetNameMapper(mapper)
+}
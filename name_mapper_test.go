@@ -0,0 +1,23 @@
+package bsql
+
+import (
+	"fmt"
+
+	"github.com/lovego/bsql/scan"
+)
+
+func ExampleSetNameMapper() {
+	SetNameMapper(scan.NewAcronymMapper())
+	defer SetNameMapper(nil)
+
+	type user struct {
+		UserID   int64
+		HTTPCode int
+		Name     string `column:"user_name"`
+	}
+	fmt.Println(Field2Column("HTTPCode"), Columns2Fields([]string{"user_id", "home_url"}))
+	fmt.Println(ColumnsFromStruct(user{}, nil))
+	// Output:
+	// http_code [UserID HomeURL]
+	// [user_id http_code user_name]
+}
@@ -0,0 +1,101 @@
+package bsql
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// OrderedMap is a map with a comparator of its keys. Values, StructValues, StructFieldValues
+// and Set iterate its Map in the order of Less, while plain maps are iterated in the order of
+// the V literals of their keys. So the same map always generates the same sql.
+type OrderedMap struct {
+	Map  interface{}
+	Less func(a, b interface{}) bool
+}
+
+// unwrapOrderedMap return the map of an OrderedMap and its comparator.
+func unwrapOrderedMap(data interface{}) (interface{}, func(a, b interface{}) bool) {
+	if m, ok := data.(OrderedMap); ok {
+		return m.Map, m.Less
+	}
+	return data, nil
+}
+
+// sortedMapKeys return the keys of a map sorted by less, or by their V literals if less is nil.
+func sortedMapKeys(m reflect.Value, less func(a, b interface{}) bool) []reflect.Value {
+	keys := m.MapKeys()
+	if less != nil {
+		sort.Slice(keys, func(i, j int) bool {
+			return less(keys[i].Interface(), keys[j].Interface())
+		})
+		return keys
+	}
+	literals := make([]string, len(keys))
+	for i, key := range keys {
+		literals[i] = V(key.Interface())
+	}
+	sort.Sort(byLiteral{keys, literals})
+	return keys
+}
+
+// Set return the distinct values of a slice, an array or the keys of a map, like "(1,2,3)",
+// for IN lists. The values are deduped and sorted by their V literals, or by the Less of an OrderedMap.
+// An empty set is "(NULL)", which matches nothing.
+func Set(data interface{}) string {
+	return set(data, V)
+}
+
+func set(data interface{}, render func(interface{}) string) string {
+	data, less := unwrapOrderedMap(data)
+	var elems []reflect.Value
+	value := reflect.ValueOf(data)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, value.Index(i))
+		}
+	case reflect.Map:
+		elems = sortedMapKeys(value, less)
+	default:
+		if value.IsValid() {
+			elems = append(elems, value)
+		}
+	}
+
+	seen := make(map[string]bool, len(elems))
+	var distinct []reflect.Value
+	var literals []string
+	for _, elem := range elems {
+		literal := V(elem.Interface())
+		if !seen[literal] {
+			seen[literal] = true
+			distinct = append(distinct, elem)
+			literals = append(literals, literal)
+		}
+	}
+	if len(distinct) == 0 {
+		return "(NULL)"
+	}
+	if value.Kind() != reflect.Map {
+		sort.Sort(byLiteral{distinct, literals})
+	}
+	slice := make([]string, len(distinct))
+	for i, elem := range distinct {
+		slice[i] = render(elem.Interface())
+	}
+	return "(" + strings.Join(slice, ",") + ")"
+}
+
+// byLiteral sort values by their literals.
+type byLiteral struct {
+	values   []reflect.Value
+	literals []string
+}
+
+func (b byLiteral) Len() int           { return len(b.values) }
+func (b byLiteral) Less(i, j int) bool { return b.literals[i] < b.literals[j] }
+func (b byLiteral) Swap(i, j int) {
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+	b.literals[i], b.literals[j] = b.literals[j], b.literals[i]
+}
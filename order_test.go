@@ -0,0 +1,19 @@
+package bsql
+
+import "fmt"
+
+func ExampleSet() {
+	fmt.Println(Set([]int{3, 1, 2, 3, 1}))
+	fmt.Println(Set([]string{"b", "a", "b"}))
+	fmt.Println(Set(map[string]bool{"y": true, "x": true}))
+	fmt.Println(Set([]int{}))
+
+	var args Args
+	fmt.Println(args.Set([]int64{2, 1, 2}), args.List())
+	// Output:
+	// (1,2,3)
+	// ('a','b')
+	// ('x','y')
+	// (NULL)
+	// ($1,$2) [1 2]
+}
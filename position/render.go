@@ -0,0 +1,164 @@
+package position
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Options of Render.
+type Options struct {
+	Before, After int // number of context lines before and after the error line.
+	// MaxWidth truncate lines longer than it to a window around the marked column.
+	// 0 means no truncation. Redact the content before rendering, because a literal cut by the window
+	// may lose the mark a redactor recognizes it by.
+	MaxWidth int
+	Color    bool // mark the error line and range with ANSI colors.
+}
+
+const (
+	colorRed   = "\x1b[31m"
+	colorFaint = "\x1b[2m"
+	colorReset = "\x1b[0m"
+)
+
+// Render render the lines around the range [start, end) of content with a line-number gutter,
+// and mark the range by "^" under the error line. If end <= start, a single "^" is marked.
+// The range is cut at the end of the error line.
+func Render(content []rune, start, end int, opts Options) string {
+	line, column := OffsetToLineAndColumn(content, start)
+	if line <= 0 || column <= 0 {
+		return ""
+	}
+	lines := strings.Split(string(content), "\n")
+	first, last := line-opts.Before, line+opts.After
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	gutterWidth := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for i := first; i <= last; i++ {
+		lineContent := []rune(strings.TrimSuffix(lines[i-1], "\r"))
+		markStart, markEnd := -1, -1
+		if i == line {
+			markStart = column - 1
+			markEnd = markStart + 1
+			if end > start {
+				markEnd = markStart + (end - start)
+			}
+			if markEnd > len(lineContent) {
+				markEnd = len(lineContent)
+			}
+			if markEnd <= markStart {
+				markEnd = markStart + 1
+			}
+		}
+		lineContent, markStart, markEnd = truncate(lineContent, markStart, markEnd, opts.MaxWidth)
+
+		gutter := fmt.Sprintf("%*d | ", gutterWidth, i)
+		if i == line {
+			b.WriteString(opts.colored(colorRed, gutter) + string(lineContent) + "\n")
+			padding := makePadding(lineContent[:min(markStart, len(lineContent))])
+			marker := strings.Repeat("^", markEnd-markStart)
+			b.WriteString(strings.Repeat(" ", gutterWidth) + " | " +
+				string(padding) + opts.colored(colorRed, marker) + "\n")
+		} else {
+			b.WriteString(opts.colored(colorFaint, gutter+string(lineContent)) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// truncate cut line to a window of maxWidth runes around the mark, with "..." at the cut ends.
+func truncate(line []rune, markStart, markEnd, maxWidth int) ([]rune, int, int) {
+	if maxWidth <= 0 || len(line) <= maxWidth {
+		return line, markStart, markEnd
+	}
+	center := 0
+	if markStart >= 0 {
+		center = (markStart + markEnd) / 2
+	}
+	from := center - maxWidth/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + maxWidth
+	if to > len(line) {
+		to, from = len(line), len(line)-maxWidth
+	}
+	result := append([]rune{}, line[from:to]...)
+	offset := 0
+	if from > 0 {
+		result = append([]rune("..."), result...)
+		offset = 3
+	}
+	if to < len(line) {
+		result = append(result, []rune("...")...)
+	}
+	if markStart >= 0 {
+		markStart = markStart - from + offset
+		markEnd = markEnd - from + offset
+		if markStart < offset {
+			markStart = offset
+		}
+		if maxEnd := offset + (to - from); markEnd > maxEnd {
+			markEnd = maxEnd
+		}
+		if markEnd <= markStart {
+			markEnd = markStart + 1
+		}
+	}
+	return result, markStart, markEnd
+}
+
+func (opts Options) colored(color, s string) string {
+	if !opts.Color {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// TokenEnd return the end offset of the token begins at offset,
+// which is a quoted literal or identifier, or a word. It's at least offset+1.
+func TokenEnd(content []rune, offset int) int {
+	if offset < 0 || offset >= len(content) {
+		return offset + 1
+	}
+	switch quote := content[offset]; quote {
+	case '\'', '"':
+		for i := offset + 1; i < len(content); i++ {
+			if content[i] == quote {
+				if i+1 < len(content) && content[i+1] == quote {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+		return len(content)
+	}
+	i := offset
+	for i < len(content) && isWordRune(content[i]) {
+		i++
+	}
+	if i == offset {
+		return offset + 1
+	}
+	return i
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
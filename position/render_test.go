@@ -0,0 +1,49 @@
+package position
+
+import "fmt"
+
+func ExampleRender() {
+	sql := []rune("select id,\n  name,\n  agee\nfrom students\nwhere id = 1")
+	fmt.Println(Render(sql, 21, 25, Options{Before: 1, After: 1}))
+	fmt.Println(Render(sql, 21, 21, Options{}))
+	fmt.Println(Render(sql, 0, 6, Options{Before: 2, After: 10}))
+	// Output:
+	// 2 |   name,
+	// 3 |   agee
+	//   |   ^^^^
+	// 4 | from students
+	// 3 |   agee
+	//   |   ^
+	// 1 | select id,
+	//   | ^^^^^^
+	// 2 |   name,
+	// 3 |   agee
+	// 4 | from students
+	// 5 | where id = 1
+}
+
+func ExampleRender_truncate() {
+	sql := []rune("select a, b, c, d, e, f, g, h, i, j, k, l, m, n from t")
+	fmt.Println(Render(sql, 31, 32, Options{MaxWidth: 20}))
+	fmt.Println(Render(sql, 2, 4, Options{MaxWidth: 20}))
+	fmt.Println(Render([]rune("中文 select x from t"), 10, 11, Options{MaxWidth: 100}))
+	// Output:
+	// 1 | ... f, g, h, i, j, k, l...
+	//   |              ^
+	// 1 | select a, b, c, d, e...
+	//   |   ^^
+	// 1 | 中文 select x from t
+	//   |             ^
+}
+
+func ExampleRender_color() {
+	fmt.Printf("%q\n", Render([]rune("a\nb"), 2, 3, Options{Before: 1, Color: true}))
+	// Output:
+	// "\x1b[2m1 | a\x1b[0m\n\x1b[31m2 | \x1b[0mb\n  | \x1b[31m^\x1b[0m"
+}
+
+func ExampleTokenEnd() {
+	sql := []rune(`select "my col", 'it''s', name_1 + 2`)
+	fmt.Println(TokenEnd(sql, 0), TokenEnd(sql, 7), TokenEnd(sql, 17), TokenEnd(sql, 26), TokenEnd(sql, 33))
+	// Output: 6 15 24 32 34
+}
@@ -0,0 +1,289 @@
+package bsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lovego/bsql/internal/memdriver"
+	"github.com/lovego/errs"
+)
+
+// Recorder records statements and their results into a JSON fixture file,
+// which can be replayed without a database by bsqltest.Replay.
+// Set it to DB.Recorder to enable the recording mode, and Close it when the recording is done.
+type Recorder struct {
+	Path    string
+	mutex   sync.Mutex
+	records []Record
+	file    *os.File
+}
+
+// Record is a statement and its result recorded by Recorder.
+type Record struct {
+	SQL     string        `json:"sql"`
+	Args    []interface{} `json:"args,omitempty"`
+	Columns []string      `json:"columns,omitempty"`
+	// Types are the DatabaseTypeName of the columns.
+	Types        []string        `json:"types,omitempty"`
+	Rows         [][]interface{} `json:"rows,omitempty"`
+	RowsAffected int64           `json:"rowsAffected,omitempty"`
+	Error        *RecordedError  `json:"error,omitempty"`
+}
+
+type RecordedError struct {
+	Message string    `json:"message"`
+	PqError *pq.Error `json:"pqError,omitempty"`
+}
+
+func NewRecorder(path string) *Recorder {
+	return &Recorder{Path: path}
+}
+
+// Records return all the recorded records.
+func (r *Recorder) Records() []Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// LoadRecords load records from a fixture file written by Recorder.
+func LoadRecords(path string) ([]Record, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errs.Trace(err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var records []Record
+	if err := decoder.Decode(&records); err != nil {
+		return nil, errs.Trace(err)
+	}
+	return records, nil
+}
+
+// DriverRows return the recorded rows in the same types as returned by the postgres driver.
+func (record Record) DriverRows() ([][]interface{}, error) {
+	var rows = make([][]interface{}, len(record.Rows))
+	for i, row := range record.Rows {
+		rows[i] = make([]interface{}, len(row))
+		for j, cell := range row {
+			var typ string
+			if j < len(record.Types) {
+				typ = record.Types[j]
+			}
+			v, err := decodeRecordedCell(cell, typ)
+			if err != nil {
+				return nil, err
+			}
+			rows[i][j] = v
+		}
+	}
+	return rows, nil
+}
+
+// queryContext run the query on q, and record it if r is not nil.
+func (r *Recorder) queryContext(
+	ctx context.Context, q queryer, sql string, args []interface{},
+) (*sql.Rows, error) {
+	if r == nil {
+		return q.QueryContext(ctx, sql, args...)
+	}
+	var record = Record{SQL: sql, Args: args}
+	rows, err := q.QueryContext(ctx, sql, args...)
+	if err == nil {
+		err = record.readRows(rows)
+	}
+	if rows != nil {
+		rows.Close()
+	}
+	if err != nil {
+		record.setError(err)
+	}
+	if addErr := r.add(record); addErr != nil {
+		return nil, addErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	driverRows, err := record.DriverRows()
+	if err != nil {
+		return nil, errs.Trace(err)
+	}
+	return memdriver.Query(ctx, &memdriver.Rows{
+		Columns: record.Columns, Types: record.Types, Values: driverRows,
+	})
+}
+
+// execContext run the statement on q, and record it if r is not nil.
+func (r *Recorder) execContext(
+	ctx context.Context, q queryer, sql string, args []interface{},
+) (sql.Result, error) {
+	if r == nil {
+		return q.ExecContext(ctx, sql, args...)
+	}
+	var record = Record{SQL: sql, Args: args}
+	result, err := q.ExecContext(ctx, sql, args...)
+	if err == nil {
+		record.RowsAffected, err = result.RowsAffected()
+	}
+	if err != nil {
+		record.setError(err)
+	}
+	if addErr := r.add(record); addErr != nil {
+		return nil, addErr
+	}
+	return result, err
+}
+
+// Close close the fixture file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return errs.Trace(err)
+}
+
+// recordsEnd is the end of the JSON array of records in the fixture file.
+const recordsEnd = "\n]\n"
+
+// add append the record to the fixture file. The end of the JSON array is overwritten by every record,
+// so the file is always a valid JSON array, without rewriting the previous records.
+func (r *Recorder) add(record Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	content, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return errs.Trace(err)
+	}
+	if r.file == nil {
+		// records are appended to the file again after Close.
+		flag := os.O_RDWR | os.O_CREATE
+		if len(r.records) == 0 {
+			flag |= os.O_TRUNC
+		}
+		if r.file, err = os.OpenFile(r.Path, flag, 0644); err != nil {
+			return errs.Trace(err)
+		}
+	}
+	if len(r.records) == 0 {
+		_, err = r.file.WriteString("[\n  ")
+	} else if _, err = r.file.Seek(-int64(len(recordsEnd)), io.SeekEnd); err == nil {
+		_, err = r.file.WriteString(",\n  ")
+	}
+	if err == nil {
+		_, err = r.file.Write(append(content, recordsEnd...))
+	}
+	if err != nil {
+		return errs.Trace(err)
+	}
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (record *Record) readRows(rows *sql.Rows) error {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	for _, columnType := range columnTypes {
+		record.Columns = append(record.Columns, columnType.Name())
+		record.Types = append(record.Types, columnType.DatabaseTypeName())
+	}
+	for rows.Next() {
+		var values = make([]interface{}, len(columnTypes))
+		var ptrs = make([]interface{}, len(columnTypes))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i := range values {
+			values[i] = encodeRecordedCell(values[i], record.Types[i])
+		}
+		record.Rows = append(record.Rows, values)
+	}
+	return rows.Err()
+}
+
+func (record *Record) setError(err error) {
+	record.Error = &RecordedError{Message: err.Error()}
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		record.Error.PqError = pqError
+	}
+}
+
+// Err return the recorded error, as a *pq.Error if it was.
+func (e *RecordedError) Err() error {
+	if e == nil {
+		return nil
+	}
+	if e.PqError != nil {
+		return e.PqError
+	}
+	return errors.New(e.Message)
+}
+
+// encodeRecordedCell make cells readable in JSON: bytes are recorded as string except bytea.
+func encodeRecordedCell(v interface{}, typ string) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		if typ == "BYTEA" {
+			return x
+		}
+		return string(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// decodeRecordedCell decode a cell into the type returned by the postgres driver.
+func decodeRecordedCell(cell interface{}, typ string) (interface{}, error) {
+	switch x := cell.(type) {
+	case json.Number:
+		switch typ {
+		case "INT2", "INT4", "INT8", "OID":
+			return x.Int64()
+		case "FLOAT4", "FLOAT8":
+			return x.Float64()
+		default:
+			if i, err := x.Int64(); err == nil {
+				return i, nil
+			}
+			return x.Float64()
+		}
+	case float64:
+		return x, nil
+	case string:
+		switch typ {
+		case "BYTEA":
+			return base64.StdEncoding.DecodeString(x)
+		case "DATE", "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ":
+			return time.Parse(time.RFC3339Nano, x)
+		case "", "TEXT", "VARCHAR", "BPCHAR", "CHAR", "NAME":
+			return x, nil
+		default:
+			return []byte(x), nil
+		}
+	default:
+		return cell, nil
+	}
+}
@@ -0,0 +1,127 @@
+package bsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Secret wrap a value, which is sent to the driver as is,
+// but rendered as "***" in debug output, error data and tracer attributes.
+// When inlined into sql by V, it's marked by a "/*secret*/" comment, which SqlRedactor recognizes.
+func Secret(value interface{}) SecretValue {
+	return SecretValue{value}
+}
+
+type SecretValue struct {
+	value interface{}
+}
+
+const secretMark = "/*secret*/"
+
+// Reveal return the wrapped value.
+func (s SecretValue) Reveal() interface{} {
+	return s.value
+}
+
+// Value convert the wrapped value in the same way as the args collected by Args,
+// so slices, maps and structs are accepted as well.
+func (s SecretValue) Value() (driver.Value, error) {
+	arg, _ := argValue(s.value)
+	if secret, ok := arg.(SecretValue); ok {
+		return secret.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(arg)
+}
+
+func (s SecretValue) String() string {
+	return "***"
+}
+
+func (s SecretValue) GoString() string {
+	return "***"
+}
+
+func (s SecretValue) MarshalJSON() ([]byte, error) {
+	return []byte(`"***"`), nil
+}
+
+// SqlRedactor redact literals inlined into sql before it's put into debug output,
+// error data or tracer attributes.
+type SqlRedactor func(sql string) string
+
+var secretLiteralRegexp = regexp.MustCompile(
+	regexp.QuoteMeta(secretMark) + `(?:'(?:[^']|'')*'|[^\s,()]+)`,
+)
+
+// RedactSecrets redact the literals marked as secret. It's the default SqlRedactor.
+func RedactSecrets(sql string) string {
+	return secretLiteralRegexp.ReplaceAllString(sql, secretMark+"'***'")
+}
+
+var stringLiteralRegexp = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// RedactLiterals redact all the string literals, besides the literals marked as secret.
+func RedactLiterals(sql string) string {
+	return stringLiteralRegexp.ReplaceAllString(RedactSecrets(sql), "'***'")
+}
+
+func redactSql(redactor SqlRedactor, sql string) string {
+	if redactor == nil {
+		return RedactSecrets(sql)
+	}
+	return redactor(sql)
+}
+
+func redactedArgs(args []interface{}) []string {
+	var s = make([]string, 0, len(args))
+	for _, arg := range args {
+		s = append(s, redactedArg(arg))
+	}
+	return s
+}
+
+// redactedArg format arg by "%#v", except that secret values and `sql:"secret"` fields are "***".
+func redactedArg(arg interface{}) string {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !hasSecretField(v.Type()) {
+		return fmt.Sprintf("%#v", arg)
+	}
+	typ := v.Type()
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if parseSqlTag(field.Tag).Has("secret") {
+			fields = append(fields, field.Name+":***")
+		} else {
+			fields = append(fields, field.Name+":"+redactedArg(v.Field(i).Interface()))
+		}
+	}
+	return typ.String() + "{" + strings.Join(fields, ", ") + "}"
+}
+
+func hasSecretField(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if parseSqlTag(field.Tag).Has("secret") {
+			return true
+		}
+		if fieldType := field.Type; field.Anonymous {
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct && hasSecretField(fieldType) {
+				return true
+			}
+		}
+	}
+	return false
+}
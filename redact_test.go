@@ -0,0 +1,119 @@
+package bsql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/lovego/bsql/position"
+	"github.com/lovego/errs"
+)
+
+func ExampleSecret() {
+	password := Secret("p'wd")
+	fmt.Println(V(password))
+	fmt.Println(password, fmt.Sprintf("%#v", password))
+	fmt.Println(password.Value())
+	fmt.Println(Secret([]string{"a", "b"}).Value())
+	fmt.Println(Secret(map[string]int{"a": 1}).Value())
+	// Output:
+	// /*secret*/'p''wd'
+	// *** ***
+	// p'wd <nil>
+	// {"a","b"} <nil>
+	// {"a":1} <nil>
+}
+
+func ExampleRedactSecrets() {
+	sql := fmt.Sprintf(`update users set password = %s, pin = %s where name = %s`,
+		V(Secret("p'wd")), V(Secret(1234)), V("jack"))
+	fmt.Println(RedactSecrets(sql))
+	fmt.Println(RedactLiterals(sql))
+	// Output:
+	// update users set password = /*secret*/'***', pin = /*secret*/'***' where name = 'jack'
+	// update users set password = /*secret*/'***', pin = /*secret*/'***' where name = '***'
+}
+
+func ExampleStructValues_secret() {
+	type user struct {
+		Name     string
+		Password string `sql:"secret"`
+	}
+	sql := StructValues([]user{{"jack", "123"}}, []string{"Name", "Password"})
+	fmt.Println(sql)
+	fmt.Println(RedactSecrets(sql))
+	// Output:
+	// ('jack',/*secret*/'123')
+	// ('jack',/*secret*/'***')
+}
+
+func ExampleColumnsDefs_secret() {
+	type user struct {
+		Name     string
+		Password string `sql:"varchar(50); secret"`
+	}
+	fmt.Println(ColumnsDefs(user{}))
+	// Output:
+	// name text NOT NULL,
+	// password varchar(50) NOT NULL
+}
+
+func Example_argsToString() {
+	type user struct {
+		Name     string
+		Password string `sql:"secret"`
+	}
+	fmt.Println(argsToString([]interface{}{
+		1, "jack", Secret("p'wd"), user{"jack", "123"}, &user{"rose", "456"},
+	}))
+	// Output:
+	// 1 "jack" *** bsql.user{Name:"jack", Password:***} bsql.user{Name:"rose", Password:***}
+}
+
+func Example_runRedacted() {
+	var output bytes.Buffer
+	sql := `select * from users where password = ` + V(Secret("p'wd"))
+	err := run(runOptions{debug: true, debugOutput: &output, putSqlInError: true}, sql, []interface{}{Secret("token")},
+		func() (time.Time, error) { return time.Time{}, errors.New("failed") },
+	)
+	fmt.Println(bytes.Contains(output.Bytes(), []byte(`p''wd`)),
+		bytes.Contains(output.Bytes(), []byte(`token`)))
+	fmt.Println(err)
+	fmt.Println(strings.TrimSpace(err.(*errs.Error).Data().(*Error).String()))
+	// Output:
+	// false false
+	// failed
+	// select * from users where password = /*secret*/'***'
+}
+
+func Example_positionRedacted() {
+	sql := `select id from users where password = ` + V(Secret("supersecretpassword123")) + ` and agee = 1`
+	offset := strings.Index(sql, "agee") + 1
+	err := wrapError(&pq.Error{Message: `column "agee" does not exist`, Position: strconv.Itoa(offset)},
+		sql, nil, 0, runOptions{})
+	var e *Error
+	fmt.Println(As(err, &e), e.Line, e.Column)
+	fmt.Println(strings.Join(strings.Split(e.Position, "\n")[:2], "\n"))
+	// Output:
+	// true 1 59
+	// 1 | select id from users where password = /*secret*/'***' and agee = 1
+	//   |                                                           ^^^^
+}
+
+func Example_positionRedactedTruncated() {
+	sql := `select id from users where password = ` + V(Secret("supersecretpassword123")) + ` and agee = 1`
+	offset := strings.Index(sql, "agee") + 1
+	err := wrapError(&pq.Error{Message: `column "agee" does not exist`, Position: strconv.Itoa(offset)},
+		sql, nil, 0, runOptions{errorContext: position.Options{MaxWidth: 20}})
+	var e *Error
+	As(err, &e)
+	// the sql is redacted before truncated, so the secret isn't cut out of its mark.
+	fmt.Println(strings.Join(strings.Split(e.Position, "\n")[:2], "\n"))
+	// Output:
+	// 1 | ...*/'***' and agee = 1
+	//   |                ^^^^
+}
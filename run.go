@@ -1,62 +1,105 @@
 package bsql
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/lovego/bsql/position"
 	"github.com/lovego/errs"
+	"github.com/lovego/tracer"
 )
 
-func run(
-	debug bool, debugOutput io.Writer, putSqlInError bool,
-	sql string, args []interface{}, work func() (time.Time, error),
-) error {
-	if !debug {
+// runOptions are the options of DB or Tx used by run.
+type runOptions struct {
+	debug         bool
+	debugOutput   io.Writer
+	putSqlInError bool
+	sqlRedactor   SqlRedactor
+	errorContext  position.Options
+}
+
+func run(opts runOptions, sql string, args []interface{}, work func() (time.Time, error)) error {
+	var startAt = time.Now()
+	if !opts.debug {
 		_, err := work()
-		return WrapError(err, sql, putSqlInError)
+		return wrapError(err, sql, args, time.Since(startAt), opts)
 	}
-	var startAt = time.Now()
 	var scanAt, err = work()
+	var elapsed = time.Since(startAt)
 
 	var s = make([]string, 0, 5)
-	s = append(s, fmt.Sprintf("bsql: total(%s)", time.Since(startAt)))
+	s = append(s, fmt.Sprintf("bsql: total(%s)", elapsed))
 	if !scanAt.IsZero() {
 		s = append(s, fmt.Sprintf("scan(%s)", time.Since(scanAt)))
 	}
-	s = append(s, color.GreenString(sql))
+	s = append(s, color.GreenString(redactSql(opts.sqlRedactor, sql)))
 
 	if len(args) > 0 {
 		s = append(s, "\n", color.BlueString(argsToString(args)))
 	}
+	var debugOutput = opts.debugOutput
 	if debugOutput == nil {
 		debugOutput = os.Stderr
 	}
 	fmt.Fprintln(debugOutput, strings.Join(s, " "))
 
-	return WrapError(err, sql, putSqlInError)
+	return wrapError(err, sql, args, elapsed, opts)
 }
 
+// WrapError wrap err into an *errs.Error, whose Data is an *Error with the error position and sql.
+// Literals marked as secret are redacted.
 func WrapError(err error, sql string, fullSql bool) error {
+	return wrapError(err, sql, nil, 0, runOptions{putSqlInError: fullSql})
+}
+
+func wrapError(
+	err error, sql string, args []interface{}, elapsed time.Duration, opts runOptions,
+) error {
 	if err == nil {
 		return nil
 	}
-	erro := errs.Trace(err).(*errs.Error)
-	if fullSql {
-		erro.SetData(GetPosition(err, sql) + "\n" + sql)
-	} else {
-		erro.SetData(GetPosition(err, sql))
+	traced := errs.Trace(err).(*errs.Error)
+	e := &Error{
+		SQL:           redactSql(opts.sqlRedactor, sql),
+		Args:          redactedArgs(args),
+		Err:           err,
+		Elapsed:       elapsed,
+		Caller:        caller(),
+		putSqlInError: opts.putSqlInError,
 	}
-	return erro
+	if traced.GetError() != nil {
+		e.Err = traced.GetError()
+	}
+	redact := func(s string) string { return redactSql(opts.sqlRedactor, s) }
+	if As(e.Err, &e.PqError) {
+		e.Where = ParseWhere(e.PqError.Where)
+		if offset, err := strconv.Atoi(e.PqError.Position); err == nil && offset >= 1 {
+			e.Line, e.Column = position.OffsetToLineAndColumn(
+				[]rune(e.SQL), redactedOffset([]rune(sql), offset-1, redact),
+			)
+		}
+	}
+	e.Position = getPosition(err, sql, redact, opts.errorContext)
+	return traced.SetData(e)
 }
 
-func argsToString(args []interface{}) string {
-	var s = make([]string, len(args))
-	for _, arg := range args {
-		s = append(s, fmt.Sprintf("%#v", arg))
+// traceSql add the redacted sql and args to the tracer if debug is enabled.
+func traceSql(ctx context.Context, redactor SqlRedactor, sql string, args []interface{}) {
+	if !tracer.IsDebug(ctx) {
+		return
+	}
+	tracer.Tag(ctx, "sql", redactSql(redactor, sql))
+	if len(args) > 0 {
+		tracer.Tag(ctx, "args", redactedArgs(args))
 	}
-	return strings.Join(s, " ")
+}
+
+func argsToString(args []interface{}) string {
+	return strings.Join(redactedArgs(args), " ")
 }
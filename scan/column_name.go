@@ -0,0 +1,102 @@
+package scan
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/lovego/struct_tag"
+	"github.com/lovego/structs"
+)
+
+// ColumnName return the column name of a struct field. It's given by the `column` tag,
+// or the "name" option of the `sql` tag like `sql:"name:userID"`,
+// otherwise it's converted from the field name by the global NameMapper.
+func ColumnName(field reflect.StructField) string {
+	return columnName(field, GetNameMapper())
+}
+
+// ColumnNameWithMapper is like ColumnName, but use mapper if it's not nil.
+func ColumnNameWithMapper(field reflect.StructField, mapper NameMapper) string {
+	return columnName(field, orDefault(mapper))
+}
+
+func columnName(field reflect.StructField, mapper NameMapper) string {
+	if name := explicitColumnName(field.Tag); name != "" {
+		return name
+	}
+	return mapper.Field2Column(field.Name)
+}
+
+func explicitColumnName(tag reflect.StructTag) string {
+	if name, _ := struct_tag.Lookup(string(tag), "column"); name != "" {
+		return name
+	}
+	value, _ := struct_tag.Lookup(string(tag), "sql")
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "name:") {
+			return strings.TrimSpace(part[len("name:"):])
+		}
+	}
+	return ""
+}
+
+type columnFieldsKey struct {
+	typ    reflect.Type
+	mapper NameMapper
+}
+
+var columnFields sync.Map // columnFieldsKey => map[string]string
+
+// columnField return the name of the struct field whose column name is column.
+func columnField(typ reflect.Type, column string, mapper NameMapper) (string, bool) {
+	key := columnFieldsKey{typ, mapper}
+	var fields map[string]string
+	if v, ok := columnFields.Load(key); ok {
+		fields = v.(map[string]string)
+	} else {
+		fields = make(map[string]string)
+		structs.TraverseType(typ, func(field reflect.StructField) bool {
+			return struct_tag.Get(string(field.Tag), `sql`) == "-"
+		}, func(field reflect.StructField) {
+			if name := columnName(field, mapper); fields[name] == "" {
+				fields[name] = field.Name
+			}
+		})
+		columnFields.Store(key, fields)
+	}
+	field, ok := fields[column]
+	return field, ok
+}
+
+// columnFieldPath return the field path of a column path like "profile.home" in typ,
+// resolving every segment by the column names of the struct fields, then by mapper.Column2Field.
+func columnFieldPath(typ reflect.Type, column string, mapper NameMapper) (path []string) {
+	for _, segment := range strings.Split(column, ".") {
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		name, ok := "", false
+		if typ != nil && typ.Kind() == reflect.Struct {
+			name, ok = columnField(typ, segment, mapper)
+		}
+		if !ok {
+			name = mapper.Column2Field(segment)
+		}
+		path = append(path, name)
+		if field, found := structField(typ, name); found {
+			typ = field.Type
+		} else {
+			typ = nil
+		}
+	}
+	return
+}
+
+func structField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	return typ.FieldByName(name)
+}
@@ -0,0 +1,52 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+func ExampleColumnName() {
+	rows, err := memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"userID", "url", "name"},
+		Types:   []string{"INT8", "TEXT", "TEXT"},
+		Values:  [][]interface{}{{int64(1), "http://a.com", "Lili"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	var users []struct {
+		Id   int64  `column:"userID"`
+		URL  string `sql:"name:url"`
+		Name string
+	}
+	fmt.Println(Scan(rows, &users))
+	fmt.Printf("%+v\n", users)
+	// Output:
+	// <nil>
+	// [{Id:1 URL:http://a.com Name:Lili}]
+}
+
+func ExampleColumnName_nested() {
+	rows, err := memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"id", "profile.home", "profile.city"},
+		Types:   []string{"INT8", "TEXT", "TEXT"},
+		Values:  [][]interface{}{{int64(1), "http://a.com", "Paris"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	var users []struct {
+		Id      int64
+		Profile struct {
+			HomePage string `column:"home"`
+			City     string
+		}
+	}
+	fmt.Println(Scan(rows, &users))
+	fmt.Printf("%+v\n", users)
+	// Output:
+	// <nil>
+	// [{Id:1 Profile:{HomePage:http://a.com City:Paris}}]
+}
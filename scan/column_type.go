@@ -3,36 +3,55 @@ package scan
 import (
 	"database/sql"
 	"strings"
-
-	"github.com/lovego/strs"
 )
 
 type ColumnType struct {
 	FieldPath []string
 	FieldName string
 	*sql.ColumnType
+	mapper NameMapper
 }
 
+// ColumnTypes return the column types of rows, with the field paths converted by the global NameMapper.
 func ColumnTypes(rows *sql.Rows) ([]ColumnType, error) {
+	return ColumnTypesWithMapper(rows, nil)
+}
+
+// ColumnTypesWithMapper is like ColumnTypes, but use mapper if it's not nil.
+func ColumnTypesWithMapper(rows *sql.Rows, mapper NameMapper) ([]ColumnType, error) {
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
 		return nil, err
 	}
+	mapper = orDefault(mapper)
 	var columns []ColumnType
 	for _, colType := range columnTypes {
-		var path = Column2FieldPath(colType.Name())
+		var path = column2FieldPath(colType.Name(), mapper)
 		columns = append(columns, ColumnType{
 			FieldPath:  path,
 			FieldName:  strings.Join(path, "."),
 			ColumnType: colType,
+			mapper:     mapper,
 		})
 	}
 	return columns, nil
 }
 
+// Column2FieldPath convert a column like "a.b" to a field path by the global NameMapper.
 func Column2FieldPath(column string) (path []string) {
+	return column2FieldPath(column, GetNameMapper())
+}
+
+func column2FieldPath(column string, mapper NameMapper) (path []string) {
 	for _, name := range strings.Split(column, ".") {
-		path = append(path, strs.SnakeToCamel(name))
+		path = append(path, mapper.Column2Field(name))
 	}
 	return
 }
+
+func (column ColumnType) dbType() string {
+	if column.ColumnType == nil {
+		return ""
+	}
+	return column.ColumnType.DatabaseTypeName()
+}
@@ -0,0 +1,133 @@
+package scan
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/lovego/strs"
+)
+
+// NameMapper convert between struct field names and column names.
+// Implementations must be comparable, because they key the caches of struct types.
+type NameMapper interface {
+	Field2Column(field string) string
+	Column2Field(column string) string
+}
+
+type mapperHolder struct{ NameMapper }
+
+var nameMapper atomic.Value // mapperHolder
+
+// SetNameMapper set the global NameMapper, which is SnakeMapper by default.
+// A nil mapper restores the default.
+func SetNameMapper(mapper NameMapper) {
+	nameMapper.Store(mapperHolder{mapper})
+}
+
+// GetNameMapper return the global NameMapper.
+func GetNameMapper() NameMapper {
+	if holder, ok := nameMapper.Load().(mapperHolder); ok && holder.NameMapper != nil {
+		return holder.NameMapper
+	}
+	return SnakeMapper{}
+}
+
+// orDefault return the global NameMapper if mapper is nil.
+func orDefault(mapper NameMapper) NameMapper {
+	if mapper == nil {
+		return GetNameMapper()
+	}
+	return mapper
+}
+
+// SnakeMapper convert CamelCase fields to snake_case columns by strs.CamelToSnake,
+// and the reverse by strs.SnakeToCamel, like "HTTPCode" to "http_code" and "http_code" to "HttpCode".
+type SnakeMapper struct{}
+
+func (SnakeMapper) Field2Column(field string) string {
+	return strs.CamelToSnake(field)
+}
+
+func (SnakeMapper) Column2Field(column string) string {
+	return strs.SnakeToCamel(column)
+}
+
+// CommonAcronyms are the acronyms AcronymMapper uses if none is given.
+var CommonAcronyms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP",
+	"JSON", "QPS", "RAM", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI",
+	"UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// AcronymMapper is like SnakeMapper, but convert the acronyms in columns to upper case,
+// like "user_id" to "UserID" and "http_code" to "HTTPCode".
+type AcronymMapper struct {
+	acronyms map[string]bool
+}
+
+// NewAcronymMapper return an AcronymMapper of the acronyms, CommonAcronyms if none is given.
+func NewAcronymMapper(acronyms ...string) *AcronymMapper {
+	if len(acronyms) == 0 {
+		acronyms = CommonAcronyms
+	}
+	m := &AcronymMapper{acronyms: make(map[string]bool, len(acronyms))}
+	for _, acronym := range acronyms {
+		m.acronyms[strings.ToUpper(acronym)] = true
+	}
+	return m
+}
+
+func (m *AcronymMapper) Field2Column(field string) string {
+	return strs.CamelToSnake(field)
+}
+
+func (m *AcronymMapper) Column2Field(column string) string {
+	var words []string
+	for _, word := range strings.Split(column, "_") {
+		if upper := strings.ToUpper(word); m.acronyms[upper] {
+			words = append(words, upper)
+		} else if word != "" {
+			words = append(words, strings.ToUpper(word[:1])+word[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PrefixMapper add Prefix to the columns converted by Mapper, and strip it from columns
+// before converting them to fields, like "Name" to "usr_name" and "usr_name" to "Name".
+// Mapper defaults to SnakeMapper.
+type PrefixMapper struct {
+	Prefix string
+	Mapper NameMapper
+}
+
+func (m PrefixMapper) Field2Column(field string) string {
+	return m.Prefix + m.mapper().Field2Column(field)
+}
+
+func (m PrefixMapper) Column2Field(column string) string {
+	return m.mapper().Column2Field(strings.TrimPrefix(column, m.Prefix))
+}
+
+func (m PrefixMapper) mapper() NameMapper {
+	if m.Mapper == nil {
+		return SnakeMapper{}
+	}
+	return m.Mapper
+}
+
+// LowerMapper convert fields to lowercase columns without separators, like "UserName" to "username".
+// Column2Field only capitalize the first letter, but scanning into structs still find the field,
+// because columns are matched against the Field2Column of the struct fields first.
+type LowerMapper struct{}
+
+func (LowerMapper) Field2Column(field string) string {
+	return strings.ToLower(field)
+}
+
+func (LowerMapper) Column2Field(column string) string {
+	if column == "" {
+		return ""
+	}
+	return strings.ToUpper(column[:1]) + column[1:]
+}
@@ -0,0 +1,60 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+func ExampleNameMapper() {
+	mappers := []NameMapper{
+		SnakeMapper{}, NewAcronymMapper(), PrefixMapper{Prefix: "usr_"}, LowerMapper{},
+	}
+	for _, mapper := range mappers {
+		fmt.Println(
+			mapper.Field2Column("UserID"), mapper.Field2Column("HTTPCode"),
+			mapper.Column2Field(mapper.Field2Column("UserID")),
+			mapper.Column2Field(mapper.Field2Column("HTTPCode")),
+		)
+	}
+	// Output:
+	// user_id http_code UserId HttpCode
+	// user_id http_code UserID HTTPCode
+	// usr_user_id usr_http_code UserId HttpCode
+	// userid httpcode Userid Httpcode
+}
+
+func ExampleScanWithMapper() {
+	query := func() *memdriver.Rows {
+		return &memdriver.Rows{
+			Columns: []string{"usr_id", "usr_username", "usr_homeurl"},
+			Values:  [][]interface{}{{int64(1), "lili", "http://a.com"}},
+		}
+	}
+	var users []struct {
+		ID       int64
+		UserName string
+		HomeURL  string
+	}
+	rows, err := memdriver.Query(context.Background(), query())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(ScanWithMapper(rows, &users, PrefixMapper{Prefix: "usr_", Mapper: LowerMapper{}}))
+	fmt.Printf("%+v\n", users)
+
+	SetNameMapper(PrefixMapper{Prefix: "usr_", Mapper: LowerMapper{}})
+	defer SetNameMapper(nil)
+	rows, err = memdriver.Query(context.Background(), query())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(Scan(rows, &users), Column2FieldPath("usr_username.usr_homeurl"))
+	fmt.Printf("%+v\n", users)
+	// Output:
+	// <nil>
+	// [{ID:1 UserName:lili HomeURL:http://a.com}]
+	// <nil> [Username Homeurl]
+	// [{ID:1 UserName:lili HomeURL:http://a.com} {ID:1 UserName:lili HomeURL:http://a.com}]
+}
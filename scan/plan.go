@@ -0,0 +1,245 @@
+package scan
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lovego/value"
+)
+
+// scanPlan is how to scan the columns into a struct type, precomputed and cached,
+// so that fields and scanners aren't resolved again for every row.
+type scanPlan struct {
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	index   []int    // the index path of the field, nil if it can't be resolved by the struct type.
+	path    []string // the field path, resolved for every row by value.Settable if index is nil.
+	factory scannerFactory
+	// the field is promoted through embedded struct pointers, which are allocated only for non-NULL values.
+	lazy bool
+}
+
+type planKey struct {
+	typ        reflect.Type
+	mapper     NameMapper
+	columns    string // the names and database types of the columns.
+	generation uint64
+}
+
+var plans sync.Map // planKey => *scanPlan
+
+// plansGeneration is increased whenever the scanners are changed, so a plan built with the old scanners
+// at the same time isn't used any more, even if it's stored after clearPlans.
+var plansGeneration uint64
+
+// clearPlans clear the cached plans, whose scanners may be changed by RegisterScanner.
+func clearPlans() {
+	atomic.AddUint64(&plansGeneration, 1)
+	plans.Range(func(key, _ interface{}) bool {
+		plans.Delete(key)
+		return true
+	})
+}
+
+func planOf(typ reflect.Type, columns []ColumnType) *scanPlan {
+	key := planKey{typ: typ, columns: columnsKey(columns), generation: atomic.LoadUint64(&plansGeneration)}
+	if len(columns) > 0 {
+		key.mapper = orDefault(columns[0].mapper)
+	}
+	if plan, ok := plans.Load(key); ok {
+		return plan.(*scanPlan)
+	}
+	plan := &scanPlan{}
+	for _, column := range columns {
+		path := column.FieldPath
+		if column.ColumnType != nil {
+			path = columnFieldPath(typ, column.Name(), orDefault(column.mapper))
+		}
+		field := fieldPlan{path: path}
+		if index, fieldType, lazy := fieldIndex(typ, path); index != nil {
+			field.index, field.lazy = index, lazy
+			field.factory = factoryOf(fieldType, column.dbType())
+		}
+		plan.fields = append(plan.fields, field)
+	}
+	plans.Store(key, plan)
+	if atomic.LoadUint64(&plansGeneration) != key.generation {
+		plans.Delete(key) // built with the old scanners, don't leave it to clearPlans that has run.
+	}
+	return plan
+}
+
+// columnsKey return the names and database types of the columns.
+// The field names are used for the columns made without *sql.ColumnType, marked by a leading "=".
+func columnsKey(columns []ColumnType) string {
+	var keys []string
+	for _, column := range columns {
+		if column.ColumnType == nil {
+			keys = append(keys, "="+column.FieldName)
+		} else {
+			keys = append(keys, column.Name()+" "+column.dbType())
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// fieldIndex return the index path and type of the field path in typ, nil index if it's not a plain field path,
+// such as paths through interfaces or methods. lazy reports whether it goes through embedded struct pointers.
+func fieldIndex(typ reflect.Type, path []string) (index []int, fieldType reflect.Type, lazy bool) {
+	for _, name := range path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil, nil, false
+		}
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			return nil, nil, false
+		}
+		// check the embedded fields the field is promoted through.
+		embedded := typ
+		for _, i := range field.Index[:len(field.Index)-1] {
+			if embedded = embedded.Field(i).Type; embedded.Kind() == reflect.Ptr {
+				embedded, lazy = embedded.Elem(), true
+			}
+		}
+		index = append(index, field.Index...)
+		typ = field.Type
+	}
+	return index, typ, lazy
+}
+
+// fieldByIndex return the field of the index path, allocating the nil pointers on the way.
+func fieldByIndex(strct reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for strct.Kind() == reflect.Ptr {
+			if strct.IsNil() {
+				strct.Set(reflect.New(strct.Type().Elem()))
+			}
+			strct = strct.Elem()
+		}
+		strct = strct.Field(i)
+	}
+	return strct
+}
+
+// existingField return the field of the index path, false if it's through a nil pointer.
+func existingField(strct reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for strct.Kind() == reflect.Ptr {
+			if strct.IsNil() {
+				return reflect.Value{}, false
+			}
+			strct = strct.Elem()
+		}
+		strct = strct.Field(i)
+	}
+	return strct, true
+}
+
+// lazyScanner scan a column into a field promoted through embedded struct pointers.
+// The nil pointers are allocated only if the column is not NULL,
+// so they are left nil if all of their columns are NULL.
+type lazyScanner struct {
+	strct   reflect.Value
+	index   []int
+	path    []string
+	factory scannerFactory
+}
+
+func (ls *lazyScanner) Scan(src interface{}) error {
+	if src == nil {
+		if dest, ok := existingField(ls.strct, ls.index); ok {
+			return ls.factory.new(dest).Scan(nil)
+		}
+		return nil
+	}
+	dest, err := ls.allocField()
+	if err != nil {
+		return err
+	}
+	return ls.factory.new(dest).Scan(src)
+}
+
+// allocField is like fieldByIndex, but return an error if a nil embedded pointer to unexported struct
+// is on the way, which can't be allocated by reflection.
+func (ls *lazyScanner) allocField() (reflect.Value, error) {
+	strct := ls.strct
+	for _, i := range ls.index {
+		for strct.Kind() == reflect.Ptr {
+			if strct.IsNil() {
+				if !strct.CanSet() {
+					return reflect.Value{}, errors.New("bsql: can't allocate the embedded pointer to unexported struct" +
+						" for field '" + strings.Join(ls.path, ".") + "'")
+				}
+				strct.Set(reflect.New(strct.Type().Elem()))
+			}
+			strct = strct.Elem()
+		}
+		strct = strct.Field(i)
+	}
+	return strct, nil
+}
+
+// rowScanner scan rows into targets of the same type.
+// The scanners are allocated once and rebound to the target of every row.
+type rowScanner struct {
+	columns  []ColumnType
+	plan     *scanPlan
+	planType reflect.Type
+	scanners []interface{}
+}
+
+func newRowScanner(columns []ColumnType) *rowScanner {
+	return &rowScanner{columns: columns}
+}
+
+func (rs *rowScanner) scanStruct(rows *sql.Rows, target reflect.Value) error {
+	if rs.planType != target.Type() {
+		rs.plan, rs.planType = planOf(target.Type(), rs.columns), target.Type()
+		rs.scanners = make([]interface{}, len(rs.columns))
+	}
+	for i, field := range rs.plan.fields {
+		if field.lazy {
+			if rs.scanners[i] == nil {
+				rs.scanners[i] = &lazyScanner{index: field.index, path: field.path, factory: field.factory}
+			}
+			rs.scanners[i].(*lazyScanner).strct = target
+			continue
+		}
+		if field.index != nil {
+			rs.scanners[i] = field.factory.bind(rs.scanners[i], fieldByIndex(target, field.index))
+			continue
+		}
+		dest := value.Settable(target, field.path)
+		if !dest.IsValid() {
+			return errors.New("bsql: no or multiple field '" + strings.Join(field.path, ".") + "' in struct")
+		}
+		rs.scanners[i] = scannerOf(dest, rs.columns[i])
+	}
+	return rows.Scan(rs.scanners...)
+}
+
+func (rs *rowScanner) scanMap(rows *sql.Rows, target reflect.Value) error {
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(target.Type()))
+	}
+	if rs.planType != target.Type() {
+		rs.plan, rs.planType = nil, target.Type()
+		rs.scanners = make([]interface{}, len(rs.columns))
+		for i, column := range rs.columns {
+			rs.scanners[i] = &mapFieldScanner{field: column.FieldName}
+		}
+	}
+	for _, scanner := range rs.scanners {
+		scanner.(*mapFieldScanner).m = target
+	}
+	return rows.Scan(rs.scanners...)
+}
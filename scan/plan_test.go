@@ -0,0 +1,247 @@
+package scan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lovego/bsql/internal/memdriver"
+	"github.com/lovego/value"
+	"github.com/shopspring/decimal"
+)
+
+type planProfile struct {
+	City  string
+	Score decimal.Decimal
+}
+
+type planStudent struct {
+	Id        int64
+	Name      string
+	Tags      []string
+	Profile   *planProfile
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+func planRows(n int, nested bool) *memdriver.Rows {
+	rows := &memdriver.Rows{
+		Columns: []string{"id", "name", "tags", "created_at", "updated_at"},
+		Types:   []string{"INT8", "TEXT", "JSONB", "TIMESTAMPTZ", "TIMESTAMPTZ"},
+	}
+	if nested {
+		rows.Columns = append(rows.Columns, "profile.city", "profile.score")
+		rows.Types = append(rows.Types, "TEXT", "NUMERIC")
+	}
+	createdAt := time.Date(2019, 6, 19, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		row := []interface{}{int64(i + 1), "name", []byte(`["a","b"]`), createdAt, nil}
+		if i%2 == 1 {
+			row[4] = createdAt.Add(time.Hour)
+		}
+		if nested {
+			row = append(row, "city", []byte("9.5"))
+		}
+		rows.Values = append(rows.Values, row)
+	}
+	return rows
+}
+
+func ExampleScan_plan() {
+	rows, err := memdriver.Query(context.Background(), planRows(2, true))
+	if err != nil {
+		panic(err)
+	}
+	var students []planStudent
+	fmt.Println(Scan(rows, &students))
+	for _, s := range students {
+		fmt.Println(s.Id, s.Name, s.Tags, *s.Profile, s.CreatedAt, s.UpdatedAt)
+	}
+
+	rows, err = memdriver.Query(context.Background(), planRows(2, false))
+	if err != nil {
+		panic(err)
+	}
+	var maps []map[string]interface{}
+	fmt.Println(Scan(rows, &maps))
+	fmt.Println(maps[1]["Id"], maps[1]["Name"], maps[1]["Tags"])
+	// Output:
+	// <nil>
+	// 1 name [a b] {city 9.5} 2019-06-19 00:00:00 +0000 UTC <nil>
+	// 2 name [a b] {city 9.5} 2019-06-19 00:00:00 +0000 UTC 2019-06-19 01:00:00 +0000 UTC
+	// <nil>
+	// 2 name ["a","b"]
+}
+
+func BenchmarkScan_struct(b *testing.B) {
+	benchmarkScan(b, planRows(1000, false), func(rows *sql.Rows) error {
+		var students []planStudent
+		return Scan(rows, &students)
+	})
+}
+
+func BenchmarkScan_struct_legacy(b *testing.B) {
+	benchmarkScan(b, planRows(1000, false), func(rows *sql.Rows) error {
+		var students []planStudent
+		return legacyScan(rows, &students)
+	})
+}
+
+func BenchmarkScan_nestedStruct(b *testing.B) {
+	benchmarkScan(b, planRows(1000, true), func(rows *sql.Rows) error {
+		var students []planStudent
+		return Scan(rows, &students)
+	})
+}
+
+func BenchmarkScan_nestedStruct_legacy(b *testing.B) {
+	benchmarkScan(b, planRows(1000, true), func(rows *sql.Rows) error {
+		var students []planStudent
+		return legacyScan(rows, &students)
+	})
+}
+
+func BenchmarkScan_map(b *testing.B) {
+	benchmarkScan(b, planRows(1000, false), func(rows *sql.Rows) error {
+		var maps []map[string]interface{}
+		return Scan(rows, &maps)
+	})
+}
+
+func BenchmarkScan_map_legacy(b *testing.B) {
+	benchmarkScan(b, planRows(1000, false), func(rows *sql.Rows) error {
+		var maps []map[string]interface{}
+		return legacyScan(rows, &maps)
+	})
+}
+
+func benchmarkScan(b *testing.B, data *memdriver.Rows, scan func(*sql.Rows) error) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rows, err := memdriver.Query(context.Background(), data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := scan(rows); err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+	}
+}
+
+// legacyScan scan rows into a slice of structs or maps as before scan plans,
+// resolving the fields and making the scanners for every column of every row.
+func legacyScan(rows *sql.Rows, data interface{}) error {
+	columns, err := ColumnTypes(rows)
+	if err != nil {
+		return err
+	}
+	target := reflect.ValueOf(data).Elem()
+	for rows.Next() {
+		elem := reflect.New(target.Type().Elem()).Elem()
+		var scanners []interface{}
+		switch elem.Kind() {
+		case reflect.Struct:
+			for _, column := range columns {
+				field := value.Settable(elem, column.FieldPath)
+				if !field.IsValid() {
+					return errors.New("bsql: no or multiple field '" + strings.Join(column.FieldPath, ".") + "' in struct")
+				}
+				scanners = append(scanners, scannerOf(field, column))
+			}
+		case reflect.Map:
+			elem.Set(reflect.MakeMap(elem.Type()))
+			for _, column := range columns {
+				scanners = append(scanners, &mapFieldScanner{elem, column.FieldName})
+			}
+		}
+		if err := rows.Scan(scanners...); err != nil {
+			return err
+		}
+		target.Set(reflect.Append(target, elem))
+	}
+	return rows.Err()
+}
+
+type PlanAddress struct {
+	City   string
+	Street *string
+}
+
+type planContact struct {
+	Phone string
+}
+
+func ExampleScan_embeddedPointer() {
+	rows, err := memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"id", "city", "street"},
+		Values: [][]interface{}{
+			{int64(1), nil, nil}, {int64(2), "Paris", nil}, {int64(3), nil, "Main St"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	var users []struct {
+		Id int
+		*PlanAddress
+	}
+	fmt.Println(Scan(rows, &users))
+	for _, u := range users {
+		if u.PlanAddress == nil {
+			fmt.Println(u.Id, nil)
+		} else {
+			fmt.Printf("%d %q %v\n", u.Id, u.City, u.Street != nil)
+		}
+	}
+
+	var contacts []struct {
+		*planContact
+	}
+	rows, err = memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"phone"}, Values: [][]interface{}{{nil}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(Scan(rows, &contacts), contacts[0].planContact == nil)
+	rows, err = memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"phone"}, Values: [][]interface{}{{"123"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(Scan(rows, &contacts))
+	// Output:
+	// <nil>
+	// 1 <nil>
+	// 2 "Paris" false
+	// 3 "" true
+	// <nil> true
+	// sql: Scan error on column index 0, name "phone": bsql: can't allocate the embedded pointer to unexported struct for field 'Phone'
+}
+
+func ExampleScanRow_columnsWithoutType() {
+	rows, err := memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"id", "name"}, Values: [][]interface{}{{int64(1), "Lili"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	columns := []ColumnType{
+		{FieldPath: []string{"Id"}, FieldName: "Id"}, {FieldPath: []string{"Name"}, FieldName: "Name"},
+	}
+	var user struct {
+		Id   int64
+		Name string
+	}
+	rows.Next()
+	fmt.Println(ScanRow(rows, columns, reflect.ValueOf(&user).Elem()), user.Id, user.Name)
+	// Output: <nil> 1 Lili
+}
@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ScanFunc decode src into dest, which is settable. src is nil for NULL,
+// or one of int64, float64, bool, []byte, string and time.Time.
+type ScanFunc func(src interface{}, dest reflect.Value) error
+
+type scannerKey struct {
+	typ    reflect.Type
+	dbType string
+}
+
+var scanners sync.Map // scannerKey => ScanFunc
+
+// RegisterScanner register a func to decode columns of dbType into destinations of typ,
+// so domain types need not implement sql.Scanner. typ can be nil to match any Go type,
+// and dbType can be empty to match any column type. dbType is a DatabaseTypeName like "POINT".
+// Registered scanners take precedence over sql.Scanner and the builtin rules,
+// the most specific one is used if multiple ones match. A nil scan unregisters the key.
+// For a pointer destination, the scanner of its element type is used, and nil is set for NULL.
+func RegisterScanner(typ reflect.Type, dbType string, scan ScanFunc) {
+	if typ == nil && dbType == "" {
+		log.Panic("bsql: RegisterScanner with neither type nor dbType.")
+	}
+	key := scannerKey{typ, strings.ToUpper(dbType)}
+	if scan == nil {
+		scanners.Delete(key)
+	} else {
+		scanners.Store(key, scan)
+	}
+	clearPlans()
+}
+
+func loadScanner(typ reflect.Type, dbType string) ScanFunc {
+	if scan, ok := scanners.Load(scannerKey{typ, dbType}); ok {
+		return scan.(ScanFunc)
+	}
+	return nil
+}
+
+// registeredScanFunc return the registered scanner for destinations of typ, nil if none.
+// ptr reports whether typ is a pointer to the registered type.
+func registeredScanFunc(typ reflect.Type, dbType string) (scan ScanFunc, ptr bool) {
+	isPtr := typ.Kind() == reflect.Ptr
+	for _, key := range []scannerKey{{typ, dbType}, {typ, ""}} {
+		if scan := loadScanner(key.typ, key.dbType); scan != nil {
+			return scan, false
+		}
+	}
+	if isPtr {
+		for _, key := range []scannerKey{{typ.Elem(), dbType}, {typ.Elem(), ""}} {
+			if scan := loadScanner(key.typ, key.dbType); scan != nil {
+				return scan, true
+			}
+		}
+	}
+	if dbType != "" {
+		if scan := loadScanner(nil, dbType); scan != nil {
+			return scan, isPtr
+		}
+	}
+	return nil, false
+}
+
+type funcScanner struct {
+	dest reflect.Value
+	scan ScanFunc
+	ptr  bool // dest is a pointer to the registered type.
+}
+
+func (fs *funcScanner) Scan(src interface{}) error {
+	if !fs.ptr {
+		return fs.scan(src, fs.dest)
+	}
+	if src == nil {
+		fs.dest.Set(reflect.Zero(fs.dest.Type()))
+		return nil
+	}
+	if fs.dest.IsNil() {
+		fs.dest.Set(reflect.New(fs.dest.Type().Elem()))
+	}
+	return fs.scan(src, fs.dest.Elem())
+}
@@ -0,0 +1,64 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lovego/bsql/internal/memdriver"
+)
+
+type phone string
+
+type point struct{ X, Y float64 }
+
+func ExampleRegisterScanner() {
+	// any column scanned into phone.
+	RegisterScanner(reflect.TypeOf(phone("")), "", func(src interface{}, dest reflect.Value) error {
+		s, _ := src.(string)
+		dest.SetString(strings.Replace(s, "-", "", -1))
+		return nil
+	})
+	// POINT columns scanned into anything, and into string specially.
+	RegisterScanner(nil, "point", func(src interface{}, dest reflect.Value) error {
+		var p point
+		if src != nil {
+			parts := strings.Split(strings.Trim(string(src.([]byte)), "()"), ",")
+			p.X, _ = strconv.ParseFloat(parts[0], 64)
+			p.Y, _ = strconv.ParseFloat(parts[1], 64)
+		}
+		dest.Set(reflect.ValueOf(p))
+		return nil
+	})
+	RegisterScanner(reflect.TypeOf(""), "POINT", func(src interface{}, dest reflect.Value) error {
+		dest.SetString("point" + string(src.([]byte)))
+		return nil
+	})
+	defer RegisterScanner(reflect.TypeOf(phone("")), "", nil)
+	defer RegisterScanner(nil, "POINT", nil)
+	defer RegisterScanner(reflect.TypeOf(""), "POINT", nil)
+
+	rows, err := memdriver.Query(context.Background(), &memdriver.Rows{
+		Columns: []string{"phone", "location", "home", "office"},
+		Types:   []string{"TEXT", "POINT", "POINT", "POINT"},
+		Values: [][]interface{}{
+			{"138-0000-0000", []byte("(1,2.5)"), []byte("(3,4)"), nil},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	var shops []struct {
+		Phone    phone
+		Location point
+		Home     string
+		Office   *point
+	}
+	fmt.Println(Scan(rows, &shops))
+	fmt.Printf("%+v\n", shops)
+	// Output:
+	// <nil>
+	// [{Phone:13800000000 Location:{X:1 Y:2.5} Home:point(3,4) Office:<nil>}]
+}
@@ -4,10 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
-	"strings"
 	"time"
-
-	"github.com/lovego/value"
 )
 
 // data must be a sql.Scanner or a non nil pointer.
@@ -17,6 +14,11 @@ import (
 // If target is a slice, it scan all rows into the slice, otherwise it scan a single row.
 // args reuse: reuse the data if is slice
 func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
+	return ScanWithMapper(rows, data, nil, reuse...)
+}
+
+// ScanWithMapper is like Scan, but map columns to fields by mapper if it's not nil.
+func ScanWithMapper(rows *sql.Rows, data interface{}, mapper NameMapper, reuse ...bool) error {
 	if scanner := trySqlScanner(data); scanner != nil {
 		if rows.Next() {
 			if err := rows.Scan(scanner); err != nil {
@@ -33,7 +35,7 @@ func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
 	if ptr.IsNil() {
 		return errors.New("bsql: data is a nil pointer.")
 	}
-	columns, err := ColumnTypes(rows)
+	columns, err := ColumnTypesWithMapper(rows, mapper)
 	if err != nil {
 		return err
 	}
@@ -42,6 +44,7 @@ func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
 	}
 
 	target := ptr.Elem()
+	rs := newRowScanner(columns)
 	switch target.Kind() {
 	case reflect.Slice:
 		typ := target.Type().Elem()
@@ -53,7 +56,7 @@ func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
 			} else {
 				elem = reflect.New(typ).Elem()
 			}
-			if err := ScanRow(rows, columns, elem); err != nil {
+			if err := rs.scan(rows, elem); err != nil {
 				return err
 			}
 			if len(reuse) > 0 && reuse[0] && target.Len() > i {
@@ -64,7 +67,7 @@ func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
 		}
 	default:
 		if rows.Next() {
-			if err := ScanRow(rows, columns, target); err != nil {
+			if err := rs.scan(rows, target); err != nil {
 				return err
 			}
 		}
@@ -75,61 +78,27 @@ func Scan(rows *sql.Rows, data interface{}, reuse ...bool) error {
 // If target is a struct, it scan all columns into the struct, otherwise it scan a single column.
 // No indirect is performed, because nil value should be set to pointer.
 func ScanRow(rows *sql.Rows, columns []ColumnType, target reflect.Value) error {
+	return newRowScanner(columns).scan(rows, target)
+}
+
+func (rs *rowScanner) scan(rows *sql.Rows, target reflect.Value) error {
 	addr := target.Addr().Interface()
 	if scanner := trySqlScanner(addr); scanner != nil {
 		return rows.Scan(scanner)
 	}
 	switch addr.(type) {
 	case *time.Time:
-		return rows.Scan(scannerOf(target, columns[0]))
+		return rows.Scan(scannerOf(target, rs.columns[0]))
 	}
 
 	switch target.Kind() {
 	case reflect.Struct:
-		if err := scan2Struct(rows, columns, target); err != nil {
-			return err
-		}
+		return rs.scanStruct(rows, target)
 	case reflect.Map:
-		if err := scan2Map(rows, columns, target); err != nil {
-			return err
-		}
+		return rs.scanMap(rows, target)
 	default:
-		if err := rows.Scan(scannerOf(target, columns[0])); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func scan2Struct(rows *sql.Rows, columns []ColumnType, target reflect.Value) error {
-	var scanners []interface{}
-	for _, column := range columns {
-		field := value.Settable(target, column.FieldPath)
-		if !field.IsValid() {
-			return errors.New("bsql: no or multiple field '" +
-				strings.Join(column.FieldPath, ".") + "' in struct")
-		}
-		scanners = append(scanners, scannerOf(field, column))
-	}
-	if err := rows.Scan(scanners...); err != nil {
-		return err
-	}
-	return nil
-}
-
-func scan2Map(rows *sql.Rows, columns []ColumnType, target reflect.Value) error {
-	if target.IsNil() {
-		target.Set(reflect.MakeMap(target.Type()))
-	}
-
-	var scanners []interface{}
-	for _, column := range columns {
-		scanners = append(scanners, &mapFieldScanner{target, column.FieldName})
+		return rows.Scan(scannerOf(target, rs.columns[0]))
 	}
-	if err := rows.Scan(scanners...); err != nil {
-		return err
-	}
-	return nil
 }
 
 type mapFieldScanner struct {
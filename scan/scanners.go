@@ -10,30 +10,80 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// when JSON/JSONB column use jsonScanner, when ARRAY column use pq.Array,
-// otherwise use basicScanner.
+// use the registered scanner if any, when JSON/JSONB column use jsonScanner,
+// when ARRAY column use pq.Array, otherwise use basicScanner.
 // Because sql.Rows.Scan's builtin logic can't scan nil to int/string,
 // so we always return a sql.Scanner to avoid its builtin logic.
 func scannerOf(dest reflect.Value, column ColumnType) sql.Scanner {
-	addr := dest.Addr()
-	if scanner := trySqlScanner(addr.Interface()); scanner != nil {
-		return scanner
-	}
+	return factoryOf(dest.Type(), column.dbType()).new(dest)
+}
+
+type scannerKind uint8
+
+const (
+	basicKind scannerKind = iota
+	registeredKind
+	sqlScannerKind
+	jsonKind
+	arrayKind
+)
+
+// scannerFactory make the scanners for destinations of a type and columns of a database type.
+type scannerFactory struct {
+	kind scannerKind
+	scan ScanFunc // the registered scanner.
+	ptr  bool     // the destination is a pointer to the registered type.
+}
 
-	var dbType string
-	if column.ColumnType != nil {
-		dbType = column.ColumnType.DatabaseTypeName()
+var sqlScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+func factoryOf(typ reflect.Type, dbType string) scannerFactory {
+	if scan, ptr := registeredScanFunc(typ, dbType); scan != nil {
+		return scannerFactory{kind: registeredKind, scan: scan, ptr: ptr}
+	}
+	if reflect.PtrTo(typ).Implements(sqlScannerType) {
+		return scannerFactory{kind: sqlScannerKind}
 	}
 	switch dbType {
 	case "JSONB", "JSON":
-		return &jsonScanner{dest}
+		return scannerFactory{kind: jsonKind}
 	default:
 		if len(dbType) > 0 && dbType[0] == '_' {
-			return pq.Array(addr.Interface())
-		} else {
-			return &basicScanner{dest}
+			return scannerFactory{kind: arrayKind}
 		}
+		return scannerFactory{kind: basicKind}
+	}
+}
+
+func (f scannerFactory) new(dest reflect.Value) sql.Scanner {
+	switch f.kind {
+	case registeredKind:
+		return &funcScanner{dest: dest, scan: f.scan, ptr: f.ptr}
+	case sqlScannerKind:
+		return trySqlScanner(dest.Addr().Interface())
+	case jsonKind:
+		return &jsonScanner{dest}
+	case arrayKind:
+		return pq.Array(dest.Addr().Interface())
+	default:
+		return &basicScanner{dest}
+	}
+}
+
+// bind rebind the scanner made by the factory before to dest, or make a new one if it can't be rebound.
+func (f scannerFactory) bind(scanner interface{}, dest reflect.Value) sql.Scanner {
+	switch s := scanner.(type) {
+	case *funcScanner:
+		s.dest = dest
+		return s
+	case *jsonScanner:
+		s.dest = dest
+		return s
+	case *basicScanner:
+		s.dest = dest
+		return s
 	}
+	return f.new(dest)
 }
 
 type jsonScanner struct {
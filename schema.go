@@ -18,11 +18,16 @@ type Table struct {
 
 // Sql add create table sql
 // Using Name as table name, Desc as table description, Struct struct as table columns and comments.
-func (t Table) Sql() string {
+// If schema is provided, it replaces the schema in Name (if any), so the same table set can be
+// created in different schemas, eg. one schema per tenant.
+func (t Table) Sql(schema ...string) string {
 	t.Name = strings.TrimSpace(t.Name)
 	if t.Name == "" {
 		log.Panic("table name required")
 	}
+	if len(schema) > 0 && strings.TrimSpace(schema[0]) != "" {
+		t.Name = strings.TrimSpace(schema[0]) + "." + t.Name[strings.LastIndexByte(t.Name, '.')+1:]
+	}
 	t.Desc = strings.TrimSpace(t.Desc)
 	if t.Desc == "" {
 		log.Panic("table desc required")
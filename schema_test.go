@@ -97,3 +97,23 @@ func ExampleTableSql_3() {
 	// COMMENT ON COLUMN pur.orders.field1 IS 'field1';
 	// COMMENT ON COLUMN pur.orders.field2 IS 'field2';
 }
+
+func ExampleTable_Sql_schema() {
+	fmt.Println(Table{
+		Name:   `pur.orders`,
+		Desc:   `采购单`,
+		Struct: &fakeTable{},
+	}.Sql("tenant1"))
+	// Output:
+	// CREATE TABLE IF NOT EXISTS tenant1.orders (
+	//   id serial8 PRIMARY KEY default null,
+	//   name text default null,
+	//   field1 text NOT NULL,
+	//   field2 text NOT NULL
+	// );
+	// COMMENT ON TABLE tenant1.orders is '采购单';
+	// COMMENT ON COLUMN tenant1.orders.id IS '主键ID';
+	// COMMENT ON COLUMN tenant1.orders.name IS '名称';
+	// COMMENT ON COLUMN tenant1.orders.field1 IS 'field1';
+	// COMMENT ON COLUMN tenant1.orders.field2 IS 'field2';
+}
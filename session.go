@@ -0,0 +1,88 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/lovego/errs"
+)
+
+// WithSessionSettings return a copy of db, which applies the settings
+// (search_path, role, application_name, custom GUCs like app.tenant_id, etc.)
+// with transaction scope in every transaction and statement it runs.
+// Statements out of transaction are run in an implicit transaction,
+// so the settings never leak to other users of the pooled connection.
+func (db *DB) WithSessionSettings(settings map[string]string) *DB {
+	copied := *db
+	copied.SessionSettings = make(map[string]string, len(db.SessionSettings)+len(settings))
+	for name, value := range db.SessionSettings {
+		copied.SessionSettings[name] = value
+	}
+	for name, value := range settings {
+		copied.SessionSettings[name] = value
+	}
+	return &copied
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// inSession run fn directly on db if no session settings,
+// otherwise run fn in a transaction with the settings applied.
+func (db *DB) inSession(ctx context.Context, fn func(queryer) error) error {
+	if len(db.SessionSettings) == 0 {
+		return fn(db.DB)
+	}
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return errs.Trace(err)
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			_ = tx.Rollback()
+			panic(err)
+		}
+	}()
+	if err := applySessionSettings(ctx, tx, db.SessionSettings); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errs.Trace(err)
+	}
+	return nil
+}
+
+func applySessionSettings(ctx context.Context, tx *sql.Tx, settings map[string]string) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, sessionSettingsSql(settings)); err != nil {
+		return errs.Trace(err)
+	}
+	return nil
+}
+
+// sessionSettingsSql use set_config(name, value, true), which is the same as "SET LOCAL",
+// but takes the value as a string, so list values like search_path can be quoted safely.
+func sessionSettingsSql(settings map[string]string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	calls := make([]string, 0, len(names))
+	for _, name := range names {
+		calls = append(calls, "set_config("+Q(name)+", "+Q(settings[name])+", true)")
+	}
+	return "SELECT " + strings.Join(calls, ", ")
+}
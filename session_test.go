@@ -0,0 +1,47 @@
+package bsql
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleDB_WithSessionSettings() {
+	db := getTestDB()
+	tenantDB := db.WithSessionSettings(map[string]string{
+		"search_path": "tenant1, public", "app.tenant_id": "1",
+	})
+	fmt.Println(len(db.SessionSettings), len(tenantDB.SessionSettings))
+	fmt.Println(sessionSettingsSql(tenantDB.SessionSettings))
+	// Output:
+	// 0 2
+	// SELECT set_config('app.tenant_id', '1', true), set_config('search_path', 'tenant1, public', true)
+}
+
+func TestSessionSettings(t *testing.T) {
+	db := getTestDB().WithSessionSettings(map[string]string{"app.tenant_id": "t'1"})
+	var tenantId string
+	if err := db.Query(&tenantId, `select current_setting('app.tenant_id')`); err != nil {
+		t.Fatal(err)
+	}
+	if tenantId != "t'1" {
+		t.Errorf("unexpected tenant id: %s", tenantId)
+	}
+	if err := db.RunInTransaction(func(tx *Tx) error {
+		return tx.Query(&tenantId, `select current_setting('app.tenant_id')`)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if tenantId != "t'1" {
+		t.Errorf("unexpected tenant id: %s", tenantId)
+	}
+
+	// settings should not leak to the pooled connection.
+	if err := getTestDB().Query(
+		&tenantId, `select coalesce(current_setting('app.tenant_id', true), '')`,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if tenantId != "" {
+		t.Errorf("session settings leaked: %s", tenantId)
+	}
+}
@@ -0,0 +1,225 @@
+package bsql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/lib/pq"
+	"github.com/lovego/errs"
+)
+
+// SqlState is a SQLSTATE error code or class, see
+// https://www.postgresql.org/docs/current/errcodes-appendix.html.
+// It's used as a sentinel error with Is or errors.Is.
+type SqlState struct {
+	Code string // a 5 characters code, or a 2 characters class.
+	Name string
+}
+
+func (s *SqlState) Error() string {
+	return "bsql: " + s.Name + " (" + s.Code + ")"
+}
+
+// Match report whether the SQLSTATE code belongs to s.
+func (s *SqlState) Match(code string) bool {
+	if len(s.Code) == 2 {
+		return len(code) == 5 && code[:2] == s.Code
+	}
+	return code == s.Code
+}
+
+// SQLSTATE classes
+var (
+	ErrClassConnectionException              = &SqlState{"08", "connection_exception"}
+	ErrClassIntegrityConstraintViolation     = &SqlState{"23", "integrity_constraint_violation"}
+	ErrClassTransactionRollback              = &SqlState{"40", "transaction_rollback"}
+	ErrClassSyntaxErrorOrAccessRuleViolation = &SqlState{"42", "syntax_error_or_access_rule_violation"}
+	ErrClassInsufficientResources            = &SqlState{"53", "insufficient_resources"}
+	ErrClassObjectNotInPrerequisiteState     = &SqlState{"55", "object_not_in_prerequisite_state"}
+	ErrClassOperatorIntervention             = &SqlState{"57", "operator_intervention"}
+)
+
+// SQLSTATE codes
+var (
+	ErrNotNullViolation     = &SqlState{"23502", "not_null_violation"}
+	ErrForeignKeyViolation  = &SqlState{"23503", "foreign_key_violation"}
+	ErrUniqueViolation      = &SqlState{"23505", "unique_violation"}
+	ErrCheckViolation       = &SqlState{"23514", "check_violation"}
+	ErrExclusionViolation   = &SqlState{"23P01", "exclusion_violation"}
+	ErrSerializationFailure = &SqlState{"40001", "serialization_failure"}
+	ErrDeadlockDetected     = &SqlState{"40P01", "deadlock_detected"}
+	ErrUndefinedColumn      = &SqlState{"42703", "undefined_column"}
+	ErrUndefinedTable       = &SqlState{"42P01", "undefined_table"}
+	ErrLockNotAvailable     = &SqlState{"55P03", "lock_not_available"}
+	ErrQueryCanceled        = &SqlState{"57014", "query_canceled"}
+)
+
+// SqlError wraps a *pq.Error, so it can be matched by SqlState sentinels with errors.Is.
+// Get it from the errors returned by Query and Exec by As or AsSqlError,
+// whose GetError is still the *pq.Error.
+type SqlError struct {
+	Code       string
+	Schema     string
+	Table      string
+	Column     string
+	Constraint string
+	Detail     string
+	Cause      *pq.Error
+}
+
+func NewSqlError(pqError *pq.Error) *SqlError {
+	return &SqlError{
+		Code: string(pqError.Code), Schema: pqError.Schema, Table: pqError.Table,
+		Column: pqError.Column, Constraint: pqError.Constraint, Detail: pqError.Detail,
+		Cause: pqError,
+	}
+}
+
+func (e *SqlError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e *SqlError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *SqlError) Is(target error) bool {
+	state, ok := target.(*SqlState)
+	return ok && state.Match(e.Code)
+}
+
+// Is is like errors.Is, but it also unwraps *errs.Error, which has no Unwrap method.
+// SqlState targets match *pq.Error in the chain by SQLSTATE code.
+func Is(err, target error) bool {
+	for ; err != nil; err = unwrapErrs(err) {
+		if errors.Is(err, target) {
+			return true
+		}
+		if state, ok := target.(*SqlState); ok {
+			var pqError *pq.Error
+			if errors.As(err, &pqError) && state.Match(string(pqError.Code)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// As is like errors.As, but it also unwraps *errs.Error, which has no Unwrap method.
+// An *Error target get the Data of the *errs.Error returned by Query and Exec,
+// and a *SqlError target get the *pq.Error in the chain as a *SqlError.
+func As(err error, target interface{}) bool {
+	switch t := target.(type) {
+	case **Error:
+		var e *errs.Error
+		for ; err != nil; err = e.GetError() {
+			if !errors.As(err, &e) {
+				return false
+			}
+			if data, ok := e.Data().(*Error); ok {
+				*t = data
+				return true
+			}
+		}
+		return false
+	case **SqlError:
+		if sqlError := AsSqlError(err); sqlError != nil {
+			*t = sqlError
+			return true
+		}
+		return false
+	}
+	for ; err != nil; err = unwrapErrs(err) {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapErrs return the error wrapped by the first *errs.Error in the chain of err.
+func unwrapErrs(err error) error {
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return e.GetError()
+	}
+	return nil
+}
+
+// AsSqlError return the *pq.Error in the chain of err as a *SqlError, or nil if there is none.
+func AsSqlError(err error) *SqlError {
+	var sqlError *SqlError
+	for e := err; e != nil; e = unwrapErrs(e) {
+		if errors.As(e, &sqlError) {
+			return sqlError
+		}
+	}
+	var pqError *pq.Error
+	if As(err, &pqError) {
+		return NewSqlError(pqError)
+	}
+	return nil
+}
+
+func IsUniqueViolation(err error) bool {
+	return Is(err, ErrUniqueViolation)
+}
+
+func IsForeignKeyViolation(err error) bool {
+	return Is(err, ErrForeignKeyViolation)
+}
+
+func IsNotNullViolation(err error) bool {
+	return Is(err, ErrNotNullViolation)
+}
+
+func IsCheckViolation(err error) bool {
+	return Is(err, ErrCheckViolation)
+}
+
+func IsExclusionViolation(err error) bool {
+	return Is(err, ErrExclusionViolation)
+}
+
+func IsSerializationFailure(err error) bool {
+	return Is(err, ErrSerializationFailure)
+}
+
+func IsDeadlockDetected(err error) bool {
+	return Is(err, ErrDeadlockDetected)
+}
+
+func IsQueryCanceled(err error) bool {
+	return Is(err, ErrQueryCanceled)
+}
+
+func IsLockNotAvailable(err error) bool {
+	return Is(err, ErrLockNotAvailable)
+}
+
+func IsUndefinedTable(err error) bool {
+	return Is(err, ErrUndefinedTable)
+}
+
+func IsUndefinedColumn(err error) bool {
+	return Is(err, ErrUndefinedColumn)
+}
+
+// IsConnectionError report whether err is a connection error, including SQLSTATE class 08,
+// admin/crash shutdown, driver.ErrBadConn, network errors and unexpected EOF.
+func IsConnectionError(err error) bool {
+	if Is(err, ErrClassConnectionException) || Is(err, driver.ErrBadConn) ||
+		Is(err, io.ErrUnexpectedEOF) || Is(err, io.EOF) {
+		return true
+	}
+	if sqlError := AsSqlError(err); sqlError != nil {
+		switch sqlError.Code {
+		case "57P01", "57P02", "57P03": // admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+	}
+	var netError net.Error
+	return As(err, &netError)
+}
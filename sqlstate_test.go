@@ -0,0 +1,79 @@
+package bsql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/lovego/errs"
+)
+
+func ExampleIs() {
+	pqError := &pq.Error{
+		Code: "23505", Table: "users", Constraint: "users_phone_key",
+		Detail: "Key (phone)=(123) already exists.",
+	}
+	for _, err := range []error{
+		pqError,
+		errs.Trace(pqError),
+		WrapError(pqError, "insert into users", true),
+		fmt.Errorf("create user: %w", WrapError(pqError, "insert into users", true)),
+		fmt.Errorf("create user: %w", pqError),
+	} {
+		fmt.Println(
+			Is(err, ErrUniqueViolation), Is(err, ErrClassIntegrityConstraintViolation),
+			Is(err, ErrForeignKeyViolation), IsUniqueViolation(err),
+		)
+	}
+	// Output:
+	// true true false true
+	// true true false true
+	// true true false true
+	// true true false true
+	// true true false true
+}
+
+func ExampleSqlError() {
+	pqError := &pq.Error{
+		Code: "23503", Table: "orders", Constraint: "orders_user_id_fkey",
+		Detail: `Key (user_id)=(9) is not present in table "users".`,
+	}
+	err := WrapError(pqError, "insert into orders", false)
+
+	var sqlError *SqlError
+	fmt.Println(As(err, &sqlError))
+	fmt.Println(sqlError.Code, sqlError.Table, sqlError.Constraint, sqlError.Detail)
+	fmt.Println(errors.Is(sqlError, ErrForeignKeyViolation), errors.Is(sqlError, ErrUniqueViolation))
+
+	var cause *pq.Error
+	fmt.Println(errors.As(fmt.Errorf("wrapped: %w", sqlError), &cause), cause == pqError)
+	fmt.Println(AsSqlError(errors.New("not sql error")))
+	// Output:
+	// true
+	// 23503 orders orders_user_id_fkey Key (user_id)=(9) is not present in table "users".
+	// true false
+	// true true
+	// <nil>
+}
+
+func ExampleIsConnectionError() {
+	fmt.Println(
+		IsConnectionError(errs.Trace(driver.ErrBadConn)),
+		IsConnectionError(&pq.Error{Code: "08006"}),
+		IsConnectionError(WrapError(&pq.Error{Code: "57P01"}, "select 1", false)),
+		IsConnectionError(&pq.Error{Code: "57014"}),
+	)
+	// Output: true true true false
+}
+
+func ExampleConflictedUniqueIndex() {
+	pqError := &pq.Error{Code: "23505", Constraint: "users_phone_key"}
+	fmt.Println(ConflictedUniqueIndex(WrapError(pqError, "insert into users", false)))
+	fmt.Println(ConflictedUniqueIndex(fmt.Errorf("wrapped: %w", errs.Trace(pqError))))
+	fmt.Println(ConflictedUniqueIndex(&pq.Error{Code: "23503"}) == "")
+	// Output:
+	// users_phone_key
+	// users_phone_key
+	// true
+}
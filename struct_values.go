@@ -1,6 +1,7 @@
 package bsql
 
 import (
+	"encoding/json"
 	"log"
 	"reflect"
 	"strings"
@@ -8,23 +9,37 @@ import (
 	"github.com/lovego/value"
 )
 
+// StructValues return the contents following the sql keyword "VALUES" of the struct fields.
+// Zero fields with `sql:"default"` tag are rendered as DEFAULT.
 func StructValues(data interface{}, fields []string) string {
+	return structValues(data, fields, V, false)
+}
+
+// StructValuesOmitZero is like StructValues, but all the zero fields are rendered as DEFAULT.
+func StructValuesOmitZero(data interface{}, fields []string) string {
+	return structValues(data, fields, V, true)
+}
+
+func structValues(
+	data interface{}, fields []string, render func(interface{}) string, omitZero bool,
+) string {
+	data, less := unwrapOrderedMap(data)
 	value := reflect.ValueOf(data)
 	switch value.Kind() {
 	case reflect.Slice, reflect.Array:
 		var slice []string
 		for i := 0; i < value.Len(); i++ {
-			slice = append(slice, "("+StructFieldsReflect(value.Index(i), fields)+")")
+			slice = append(slice, "("+structFields(value.Index(i), fields, render, omitZero)+")")
 		}
 		return strings.Join(slice, ",")
 	case reflect.Map:
 		var slice []string
-		for _, key := range value.MapKeys() {
-			slice = append(slice, "("+StructFieldsReflect(key, fields)+")")
+		for _, key := range sortedMapKeys(value, less) {
+			slice = append(slice, "("+structFields(key, fields, render, omitZero)+")")
 		}
 		return strings.Join(slice, ",")
 	default:
-		return "(" + StructFieldsReflect(value, fields) + ")"
+		return "(" + structFields(value, fields, render, omitZero) + ")"
 	}
 }
 
@@ -33,6 +48,12 @@ func StructFields(value interface{}, fields []string) string {
 }
 
 func StructFieldsReflect(value reflect.Value, fields []string) string {
+	return structFields(value, fields, V, false)
+}
+
+func structFields(
+	value reflect.Value, fields []string, render func(interface{}) string, omitZero bool,
+) string {
 	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
 		value = value.Elem()
 	}
@@ -45,27 +66,45 @@ func StructFieldsReflect(value reflect.Value, fields []string) string {
 		if !field.IsValid() {
 			log.Panic("bsql: no field '" + fieldName + "' in struct")
 		}
-		slice = append(slice, V(field.Interface()))
+		if field.IsZero() && (omitZero || hasDefault(value.Type(), fieldName)) {
+			slice = append(slice, "DEFAULT")
+		} else {
+			slice = append(slice, fieldLiteral(value, fieldName, field, render))
+		}
 	}
 	return strings.Join(slice, ",")
 }
 
+// StructValuesWithType is like StructValues, but the values are casted to the column types.
+// DEFAULT isn't allowed where the types are needed, like "FROM (VALUES ...)", so zero fields with
+// `sql:"default"` tag are rendered as the default expression given by the "default:expr" option
+// or the column definition instead, and as their values if no expression is given.
 func StructValuesWithType(data interface{}, fields []string) string {
+	return structValuesWithType(data, fields, false)
+}
+
+// structValuesWithType render the values for updating if update is true,
+// with the actual values of the `sql:"default"` fields and Id casted too.
+func structValuesWithType(data interface{}, fields []string, update bool) string {
 	value := reflect.ValueOf(data)
 	typ := reflect.TypeOf(data)
 	switch value.Kind() {
 	case reflect.Slice, reflect.Array:
 		var slice []string
 		for i := 0; i < value.Len(); i++ {
-			slice = append(slice, StructFieldsWithType(value.Index(i), typ.Elem(), fields))
+			slice = append(slice, structFieldsWithType(value.Index(i), typ.Elem(), fields, update))
 		}
 		return strings.Join(slice, ",")
 	default:
-		return StructFieldsWithType(value, typ, fields)
+		return structFieldsWithType(value, typ, fields, update)
 	}
 }
 
 func StructFieldsWithType(value reflect.Value, typ reflect.Type, fields []string) string {
+	return structFieldsWithType(value, typ, fields, false)
+}
+
+func structFieldsWithType(value reflect.Value, typ reflect.Type, fields []string, update bool) string {
 	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
 		value = value.Elem()
 	}
@@ -82,38 +121,143 @@ func StructFieldsWithType(value reflect.Value, typ reflect.Type, fields []string
 			log.Panic("bsql: no field '" + fieldName + "' in struct")
 		}
 		var fieldType string
-		if fieldName != `Id` {
+		if fieldName != `Id` || update {
 			getValue, ok := typ.FieldByName(fieldName)
 			if !ok {
 				log.Panic("bsql: no field '" + fieldName + "' in struct")
 			}
-			fieldType = "::" + getColumnType(getValue)
+			fieldType = "::" + castType(fieldColumnType(getValue))
 		}
-		slice = append(slice, V(field.Interface())+fieldType)
+		// DEFAULT isn't allowed here, so use the default expression instead.
+		if tag, ok := fieldTag(typ, fieldName); ok && tag.HasDefault() && field.IsZero() && !update {
+			if expr := tag.DefaultExpr(); expr != "" {
+				slice = append(slice, "("+expr+")"+fieldType)
+				continue
+			}
+		}
+		slice = append(slice, fieldV(value, fieldName, field)+fieldType)
 	}
 	return "(" + strings.Join(slice, ",") + ")"
 }
 
 func StructFieldValues(data interface{}, field string) string {
+	return structFieldValues(data, field, V)
+}
+
+func structFieldValues(data interface{}, field string, render func(interface{}) string) string {
+	data, less := unwrapOrderedMap(data)
 	value := reflect.ValueOf(data)
 	switch value.Kind() {
 	case reflect.Slice, reflect.Array:
 		var slice []string
 		for i := 0; i < value.Len(); i++ {
-			slice = append(slice, V(getValue(value.Index(i), field).Interface()))
+			slice = append(slice, fieldLiteral(value.Index(i), field, getValue(value.Index(i), field), render))
 		}
 		return "(" + strings.Join(slice, ",") + ")"
 	case reflect.Map:
 		var slice []string
-		for _, key := range value.MapKeys() {
-			slice = append(slice, V(getValue(key, field).Interface()))
+		for _, key := range sortedMapKeys(value, less) {
+			slice = append(slice, fieldLiteral(key, field, getValue(key, field), render))
 		}
 		return "(" + strings.Join(slice, ",") + ")"
 	default:
-		return "(" + V(getValue(value, field).Interface()) + ")"
+		return "(" + fieldLiteral(value, field, getValue(value, field), render) + ")"
 	}
 }
 
+func hasDefault(typ reflect.Type, fieldName string) bool {
+	tag, ok := fieldTag(typ, fieldName)
+	return ok && tag.HasDefault()
+}
+
+// getValue return the field indicated by a field path like "A.B".
+// A field promoted through a nil embedded struct pointer is returned as a nil pointer, which V renders as NULL.
 func getValue(strct reflect.Value, fieldName string) reflect.Value {
-	return value.Get(strct, strings.Split(fieldName, "."))
+	path := strings.Split(fieldName, ".")
+	if typ, ok := throughNilEmbedded(strct, path); ok {
+		return reflect.Zero(reflect.PtrTo(typ))
+	}
+	return value.Get(strct, path)
+}
+
+// throughNilEmbedded report whether the field path goes through a nil embedded struct pointer,
+// and return the type of the field if so.
+func throughNilEmbedded(strct reflect.Value, path []string) (reflect.Type, bool) {
+	for _, name := range path {
+		for strct.Kind() == reflect.Ptr || strct.Kind() == reflect.Interface {
+			if strct.IsNil() {
+				return nil, false
+			}
+			strct = strct.Elem()
+		}
+		if strct.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := strct.Type().FieldByName(name)
+		if !ok {
+			return nil, false
+		}
+		for i, index := range field.Index {
+			if i > 0 && strct.Kind() == reflect.Ptr {
+				if strct.IsNil() {
+					return field.Type, true
+				}
+				strct = strct.Elem()
+			}
+			strct = strct.Field(index)
+		}
+	}
+	return nil, false
+}
+
+// fieldV return V of a struct field, marked as secret if the field has `sql:"secret"` tag.
+func fieldV(strct reflect.Value, fieldName string, field reflect.Value) string {
+	return fieldLiteral(strct, fieldName, field, V)
+}
+
+func fieldLiteral(
+	strct reflect.Value, fieldName string, field reflect.Value, render func(interface{}) string,
+) string {
+	for strct.Kind() == reflect.Ptr || strct.Kind() == reflect.Interface {
+		strct = strct.Elem()
+	}
+	if strct.IsValid() {
+		if tag, ok := fieldTag(strct.Type(), fieldName); ok {
+			value := field.Interface()
+			if isJsonType(tag.ColumnType()) {
+				value = jsonValue(field)
+			}
+			if tag.Has("secret") {
+				value = Secret(value)
+			}
+			return render(value)
+		}
+	}
+	return render(field.Interface())
+}
+
+// jsonValue convert a slice or array, which V renders as a postgres array, to json for json columns.
+func jsonValue(field reflect.Value) interface{} {
+	v := field
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return field.Interface()
+		}
+		v = v.Elem()
+	}
+	if kind := v.Kind(); kind != reflect.Slice && kind != reflect.Array ||
+		v.Type().Implements(valuerType) || getEncoder(v.Type()) != nil {
+		return field.Interface()
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil
+	}
+	if _, ok := arrayLiteral(v); !ok {
+		return field.Interface()
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		log.Panic("bsql json.Marshal: ", err)
+	}
+	return json.RawMessage(b)
 }
@@ -3,6 +3,7 @@ package bsql
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 func ExampleStructValues() {
@@ -38,16 +39,16 @@ func ExampleStructValues_map() {
 		Id        int
 		Name, Sex string
 	}
-	m := map[string]bool{"(1,'李雷'),(2,'韩梅梅')": true, "(2,'韩梅梅'),(1,'李雷')": true}
-	result := StructValues(map[student]int{
-		student{1, "李雷", "男"}:  1,
+	data := map[student]int{
 		student{2, "韩梅梅", "女"}: 2,
-	}, []string{"Id", "Name"})
-
-	fmt.Println(m[result])
+		student{1, "李雷", "男"}:  1,
+	}
+	fmt.Println(StructValues(data, []string{"Id", "Name"}))
+	fmt.Println(StructFieldValues(data, "Name"))
 
 	// Output:
-	// true
+	// (1,'李雷'),(2,'韩梅梅')
+	// ('李雷','韩梅梅')
 }
 
 func ExampleStructFields() {
@@ -100,3 +101,79 @@ func ExampleStructFiledValues() {
 	// (1,2,3,4)
 	// ('李雷','韩梅梅','Lili','Lucy')
 }
+
+func ExampleStructValues_default() {
+	type student struct {
+		Id        int64 `sql:"default"`
+		Name      string
+		Status    string    `sql:"default 'active' not null; default"`
+		CreatedAt time.Time `sql:"default:now()"`
+		Active    bool      `sql:"bool not null default true"` // a zero value is written without the option.
+	}
+	fields := []string{"Id", "Name", "Status", "CreatedAt", "Active"}
+	createdAt := time.Date(2019, 6, 19, 0, 0, 0, 0, time.UTC)
+	data := []student{{Name: "Lili"}, {Id: 2, Name: "Lucy", Status: "left", CreatedAt: createdAt}}
+	fmt.Println(StructValues(data, fields))
+	fmt.Println(StructValuesOmitZero([]student{{Id: 3}}, fields))
+	fmt.Println(StructValuesWithType(data, fields))
+	fmt.Println(ColumnsDefs(student{}))
+	// Output:
+	// (DEFAULT,'Lili',DEFAULT,DEFAULT,false),(2,'Lucy','left','2019-06-19T00:00:00Z',false)
+	// (3,DEFAULT,DEFAULT,DEFAULT,DEFAULT)
+	// (0,'Lili'::text,('active')::text,(now())::timestamptz,false::bool),(2,'Lucy'::text,'left'::text,'2019-06-19T00:00:00Z'::timestamptz,false::bool)
+	// id serial8 NOT NULL PRIMARY KEY,
+	// name text NOT NULL,
+	// status text default 'active' not null,
+	// created_at timestamptz NOT NULL DEFAULT now(),
+	// active bool not null default true
+}
+
+func ExampleStructValues_arrayAndJson() {
+	type student struct {
+		Tags    []string
+		Cities  []string `sql:"jsonb"`
+		Scores  []int    `sql:"json not null"`
+		Friends []int64  `sql:"int8[]"`
+	}
+	fields := []string{"Tags", "Cities", "Scores", "Friends"}
+	data := []student{{Tags: []string{"x"}, Cities: []string{"成都"}, Scores: []int{90}, Friends: []int64{2}}, {}}
+	fmt.Println(StructValues(data, fields))
+	fmt.Println(StructValuesWithType(data, fields))
+	fmt.Println(ColumnsDefs(student{}))
+	// Output:
+	// ('{"x"}','["成都"]','[90]','{2}'),(NULL,NULL,NULL,NULL)
+	// ('{"x"}'::text[],'["成都"]'::jsonb,'[90]'::json,'{2}'::int8[]),(NULL::text[],NULL::jsonb,NULL::json,NULL::int8[])
+	// tags text[] NOT NULL,
+	// cities jsonb NOT NULL,
+	// scores json not null,
+	// friends int8[] NOT NULL
+}
+
+type Address struct {
+	City   string
+	Street *string
+}
+
+type contact struct {
+	Phone string
+}
+
+func ExampleStructValues_embeddedPointer() {
+	type user struct {
+		Id int
+		*Address
+		*contact
+	}
+	fields := FieldsFromStruct(user{}, nil)
+	street := "Main St"
+	data := []user{
+		{Id: 1}, {Id: 2, Address: &Address{City: "Paris", Street: &street}, contact: &contact{Phone: "123"}},
+	}
+	fmt.Println(fields)
+	fmt.Println(StructValues(data, fields))
+	fmt.Println(StructValuesWithType(data, fields))
+	// Output:
+	// [Id City Street Phone]
+	// (1,NULL,NULL,NULL),(2,'Paris','Main St','123')
+	// (1,NULL::text,NULL::text,NULL::text),(2,'Paris'::text,'Main St'::text,'123'::text)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/lovego/bsql/position"
 	"github.com/lovego/bsql/scan"
 	"github.com/lovego/errs"
 	"github.com/lovego/tracer"
@@ -17,7 +18,17 @@ type Tx struct {
 	Timeout       time.Duration // default timeout for Query or Exec.
 	Debug         bool
 	DebugOutput   io.Writer
-	PutSqlInError bool // put sql into returned error if error happend .
+	PutSqlInError bool      // put sql into returned error if error happend .
+	Recorder      *Recorder // record statements and results into a fixture file if not nil.
+	// redact literals inlined into sql in debug output, error data and tracer attributes.
+	// If nil, RedactSecrets is used.
+	SqlRedactor SqlRedactor
+	// lines of context and truncation of the error position put into error data.
+	ErrorContext position.Options
+	// map result columns to struct fields when scanning, and fields to columns in InsertStructs.
+	// The other sql helpers don't take a DB or Tx, so they use the global NameMapper.
+	// If nil, the global NameMapper is used. It must be comparable, because it keys the caches of scan plans.
+	NameMapper NameMapper
 }
 
 func NewTx(tx *sql.Tx, timeout time.Duration) *Tx {
@@ -47,7 +58,9 @@ func (tx *Tx) QueryT(duration time.Duration, data interface{}, sql string, args
 func (tx *Tx) QueryCtx(ctx context.Context, opName string,
 	data interface{}, sql string, args ...interface{},
 ) error {
-	defer tracer.Finish(tracer.StartChild(ctx, opName))
+	ctx = tracer.StartChild(ctx, opName)
+	defer tracer.Finish(ctx)
+	traceSql(ctx, tx.SqlRedactor, sql, args)
 	if ctx.Done() == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, tx.Timeout)
@@ -67,7 +80,9 @@ func (tx *Tx) ExecT(duration time.Duration, sql string, args ...interface{}) (sq
 }
 
 func (tx *Tx) ExecCtx(ctx context.Context, opName string, sql string, args ...interface{}) (sql.Result, error) {
-	defer tracer.Finish(tracer.StartChild(ctx, opName))
+	ctx = tracer.StartChild(ctx, opName)
+	defer tracer.Finish(ctx)
+	traceSql(ctx, tx.SqlRedactor, sql, args)
 	if ctx.Done() == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, tx.Timeout)
@@ -77,9 +92,9 @@ func (tx *Tx) ExecCtx(ctx context.Context, opName string, sql string, args ...in
 }
 
 func (tx *Tx) query(ctx context.Context, data interface{}, sql string, args []interface{}, reuse ...bool) error {
-	return run(tx.Debug, tx.DebugOutput, tx.PutSqlInError, sql, args,
+	return run(tx.runOptions(), sql, args,
 		func() (scanAt time.Time, err error) {
-			rows, err := tx.Tx.QueryContext(ctx, sql, args...)
+			rows, err := tx.Recorder.queryContext(ctx, tx.Tx, sql, args)
 			if rows != nil {
 				defer rows.Close()
 			}
@@ -89,7 +104,7 @@ func (tx *Tx) query(ctx context.Context, data interface{}, sql string, args []in
 			if tx.Debug {
 				scanAt = time.Now()
 			}
-			if err := scan.Scan(rows, data, reuse...); err != nil {
+			if err := scan.ScanWithMapper(rows, data, tx.NameMapper, reuse...); err != nil {
 				return scanAt, errs.Trace(err)
 			}
 			return
@@ -99,9 +114,9 @@ func (tx *Tx) query(ctx context.Context, data interface{}, sql string, args []in
 func (tx *Tx) exec(
 	ctx context.Context, sql string, args []interface{},
 ) (result sql.Result, err error) {
-	err = run(tx.Debug, tx.DebugOutput, tx.PutSqlInError, sql, args,
+	err = run(tx.runOptions(), sql, args,
 		func() (time.Time, error) {
-			result, err = tx.Tx.ExecContext(ctx, sql, args...)
+			result, err = tx.Recorder.execContext(ctx, tx.Tx, sql, args)
 			return time.Time{}, errs.Trace(err)
 		})
 	return
@@ -114,3 +129,10 @@ func (tx *Tx) context(timeout time.Duration) (context.Context, context.CancelFun
 	}
 	return context.WithTimeout(ctx, timeout)
 }
+
+func (tx *Tx) runOptions() runOptions {
+	return runOptions{
+		debug: tx.Debug, debugOutput: tx.DebugOutput, putSqlInError: tx.PutSqlInError,
+		sqlRedactor: tx.SqlRedactor, errorContext: tx.ErrorContext,
+	}
+}
@@ -0,0 +1,124 @@
+package bsql
+
+import (
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/lovego/bsql/scan"
+)
+
+// UpdateSql return the sql to update the fields of a struct in table, where the keyFields equal.
+// If fields is nil, all the fields are updated. The keyFields and the fields with `sql:"readonly"` tag
+// are never updated. If the struct has an UpdatedAt field not in fields, "updated_at = now()" is set.
+func UpdateSql(table string, data interface{}, fields, keyFields []string) string {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		log.Panic("bsql: UpdateSql data must be a struct.")
+	}
+	if fields = fieldsToUpdate(value.Type(), fields, keyFields); len(fields) == 0 {
+		log.Panic("bsql: no fields to update.")
+	}
+
+	// the actual values are set, the defaults of the `sql:"default"` fields are only for inserting.
+	var sets []string
+	for _, field := range fields {
+		sets = append(sets, fieldColumn(value.Type(), field)+" = "+updateValue(value, field))
+	}
+	var conds []string
+	for _, key := range keyFields {
+		conds = append(conds, fieldColumn(value.Type(), key)+" = "+updateValue(value, key))
+	}
+	return "UPDATE " + table + " SET " + strings.Join(append(sets, updatedAt(value.Type(), fields)...), ", ") +
+		"\nWHERE " + strings.Join(conds, " AND ")
+}
+
+// BulkUpdateSql return the sql to update the fields of a struct slice in table by one statement,
+// where the keyFields equal, like:
+//
+//	UPDATE users AS t SET name = v.name
+//	FROM (VALUES (1,'Lili'::text),(2,'Lucy'::text)) AS v(id, name)
+//	WHERE t.id = v.id
+//
+// The values are casted to the column types like StructValuesWithType, including the keyFields,
+// but the actual values of the `sql:"default"` fields are set. The fields are chosen like UpdateSql. It return an empty string if data is empty.
+func BulkUpdateSql(table string, data interface{}, fields, keyFields []string) string {
+	typ := reflect.TypeOf(data)
+	if typ == nil || typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+		log.Panic("bsql: BulkUpdateSql data must be a struct slice.")
+	}
+	typ = typ.Elem()
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		log.Panic("bsql: BulkUpdateSql data must be a struct slice.")
+	}
+	if fields = fieldsToUpdate(typ, fields, keyFields); len(fields) == 0 {
+		log.Panic("bsql: no fields to update.")
+	}
+	if reflect.ValueOf(data).Len() == 0 {
+		return ""
+	}
+
+	var sets []string
+	for _, field := range fields {
+		column := fieldColumn(typ, field)
+		sets = append(sets, column+" = v."+column)
+	}
+	var conds []string
+	for _, key := range keyFields {
+		column := fieldColumn(typ, key)
+		conds = append(conds, "t."+column+" = v."+column)
+	}
+	valueFields := append(append([]string{}, keyFields...), fields...)
+	return "UPDATE " + table + " AS t SET " +
+		strings.Join(append(sets, updatedAt(typ, fields)...), ", ") +
+		"\nFROM (VALUES " + structValuesWithType(data, valueFields, true) + ") AS v(" +
+		strings.Join(Fields2ColumnsOf(data, valueFields), ", ") + ")" +
+		"\nWHERE " + strings.Join(conds, " AND ")
+}
+
+func updateValue(strct reflect.Value, fieldName string) string {
+	field := getValue(strct, fieldName)
+	if !field.IsValid() {
+		log.Panic("bsql: no field '" + fieldName + "' in struct")
+	}
+	return fieldV(strct, fieldName, field)
+}
+
+// fieldsToUpdate exclude the keyFields, readonly fields and UpdatedAt from fields,
+// which defaults to all the fields of the struct.
+func fieldsToUpdate(typ reflect.Type, fields, keyFields []string) []string {
+	if len(keyFields) == 0 {
+		log.Panic("bsql: keyFields required to update.")
+	}
+	explicit := fields != nil
+	if !explicit {
+		traverseStructFields(typ, func(field reflect.StructField) {
+			fields = append(fields, field.Name)
+		})
+	}
+	var result []string
+	for _, field := range fields {
+		if !notIn(field, keyFields) || field == "UpdatedAt" && !explicit {
+			continue
+		}
+		if tag, ok := fieldTag(typ, field); ok && tag.Has("readonly") {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// updatedAt return "updated_at = now()" if the struct has an UpdatedAt field not in fields.
+func updatedAt(typ reflect.Type, fields []string) []string {
+	if field, ok := typ.FieldByName("UpdatedAt"); ok && notIn("UpdatedAt", fields) {
+		return []string{scan.ColumnName(field) + " = now()"}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package bsql
+
+import (
+	"fmt"
+	"time"
+)
+
+func ExampleUpdateSql() {
+	type user struct {
+		Id        int64
+		Name      string
+		Age       int
+		CreatedAt time.Time `sql:"readonly"`
+		UpdatedAt time.Time
+	}
+	u := user{Id: 1, Name: "Lili", Age: 18}
+	fmt.Println(UpdateSql("users", u, nil, []string{"Id"}))
+	fmt.Println(UpdateSql("users", &u, []string{"Age", "CreatedAt"}, []string{"Id", "Name"}))
+	// Output:
+	// UPDATE users SET name = 'Lili', age = 18, updated_at = now()
+	// WHERE id = 1
+	// UPDATE users SET age = 18, updated_at = now()
+	// WHERE id = 1 AND name = 'Lili'
+}
+
+func ExampleBulkUpdateSql() {
+	type member struct {
+		GroupId   int64
+		UserId    int64
+		Role      string
+		Score     float64
+		UpdatedAt time.Time
+	}
+	data := []member{{GroupId: 1, UserId: 2, Role: "admin", Score: 1.5}, {GroupId: 1, UserId: 3, Role: "guest"}}
+	fmt.Println(BulkUpdateSql("members", data, nil, []string{"GroupId", "UserId"}))
+	fmt.Println(BulkUpdateSql("members", data, []string{"Role", "UpdatedAt"}, []string{"GroupId", "UserId"}))
+	// Output:
+	// UPDATE members AS t SET role = v.role, score = v.score, updated_at = now()
+	// FROM (VALUES (1::int8,2::int8,'admin'::text,1.5::float8),(1::int8,3::int8,'guest'::text,0::float8)) AS v(group_id, user_id, role, score)
+	// WHERE t.group_id = v.group_id AND t.user_id = v.user_id
+	// UPDATE members AS t SET role = v.role, updated_at = v.updated_at
+	// FROM (VALUES (1::int8,2::int8,'admin'::text,'0001-01-01T00:00:00Z'::timestamptz),(1::int8,3::int8,'guest'::text,'0001-01-01T00:00:00Z'::timestamptz)) AS v(group_id, user_id, role, updated_at)
+	// WHERE t.group_id = v.group_id AND t.user_id = v.user_id
+}
+
+func ExampleBulkUpdateSql_arrayJsonAndDefault() {
+	type user struct {
+		Id     int64
+		Tags   []string
+		Cities []string `sql:"jsonb"`
+		Status string   `sql:"default 'active'; default"`
+	}
+	data := []user{{Id: 1, Tags: []string{"a"}, Cities: []string{"Paris"}}, {Id: 2, Status: "banned"}}
+	fmt.Println(BulkUpdateSql("users", data, nil, []string{"Id"}))
+	fmt.Printf("%q\n", BulkUpdateSql("users", []user{}, nil, []string{"Id"}))
+	// Output:
+	// UPDATE users AS t SET tags = v.tags, cities = v.cities, status = v.status
+	// FROM (VALUES (1::int8,'{"a"}'::text[],'["Paris"]'::jsonb,''::text),(2::int8,NULL::text[],NULL::jsonb,'banned'::text)) AS v(id, tags, cities, status)
+	// WHERE t.id = v.id
+	// ""
+}
+
+func ExampleBulkUpdateSql_keyTypes() {
+	type device struct {
+		Id     string `sql:"uuid"`
+		Active bool   `sql:"default true; default"`
+	}
+	data := []device{{Id: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}
+	fmt.Println(UpdateSql("devices", data[0], nil, []string{"Id"}))
+	fmt.Println(BulkUpdateSql("devices", data, nil, []string{"Id"}))
+	// Output:
+	// UPDATE devices SET active = false
+	// WHERE id = '6ba7b810-9dad-11d1-80b4-00c04fd430c8'
+	// UPDATE devices AS t SET active = v.active
+	// FROM (VALUES ('6ba7b810-9dad-11d1-80b4-00c04fd430c8'::uuid,false::bool)) AS v(id, active)
+	// WHERE t.id = v.id
+}
@@ -3,16 +3,20 @@ package bsql
 import (
 	"fmt"
 	"strings"
-
-	"github.com/lib/pq"
-	"github.com/lovego/errs"
 )
 
 func UpsertSql(table string, toInsert, conflictKeys, notToUpdate []string) string {
-	toInsert = Fields2Columns(toInsert)
-	conflictKeys = Fields2Columns(conflictKeys)
-	notToUpdate = Fields2Columns(notToUpdate)
+	return upsertSql(table, Fields2Columns(toInsert), Fields2Columns(conflictKeys), Fields2Columns(notToUpdate))
+}
 
+// UpsertSqlOf is like UpsertSql, but the column names given by the tags of the fields of strct are used,
+// see Fields2ColumnsOf. strct can be a struct, a struct pointer or a struct slice.
+func UpsertSqlOf(table string, strct interface{}, toInsert, conflictKeys, notToUpdate []string) string {
+	return upsertSql(table, Fields2ColumnsOf(strct, toInsert),
+		Fields2ColumnsOf(strct, conflictKeys), Fields2ColumnsOf(strct, notToUpdate))
+}
+
+func upsertSql(table string, toInsert, conflictKeys, notToUpdate []string) string {
 	// conflict keys should be inserted and not be updated.
 	for _, key := range conflictKeys {
 		if notIn(key, toInsert) {
@@ -45,12 +49,10 @@ ON CONFLICT (%s) DO UPDATE SET
 	)
 }
 
+// ConflictedUniqueIndex return the violated unique constraint name if err is a unique violation.
 func ConflictedUniqueIndex(err error) string {
-	if wrappedErr, ok := err.(*errs.Error); ok {
-		err = wrappedErr.GetError()
-	}
-	if pqError, ok := err.(*pq.Error); ok && pqError.Code == "23505" { // unique_violation
-		return pqError.Constraint
+	if sqlError := AsSqlError(err); sqlError != nil && ErrUniqueViolation.Match(sqlError.Code) {
+		return sqlError.Constraint
 	}
 	return ""
 }
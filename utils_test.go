@@ -28,3 +28,21 @@ func ExampleUpsertSql_2() {
 	// (         name,          sex,          birthday,          updated_by,          updated_at) =
 	// (excluded.name, excluded.sex, excluded.birthday, excluded.created_by, excluded.created_at)
 }
+
+func ExampleUpsertSqlOf() {
+	type user struct {
+		Phone     string
+		HomePage  string `sql:"name:url"`
+		Status    string `column:"state"`
+		CreatedAt string
+	}
+	fmt.Println(UpsertSqlOf("users", []user{}, []string{"Phone", "HomePage", "Status", "CreatedAt"},
+		[]string{"Phone"}, []string{"Status"},
+	))
+	// Output:
+	// INSERT INTO users (phone, url, state, created_at)
+	// VALUES %s
+	// ON CONFLICT (phone) DO UPDATE SET
+	// (         url,          updated_at) =
+	// (excluded.url, excluded.created_at)
+}
@@ -2,14 +2,19 @@ package bsql
 
 import (
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"log"
+	"math/big"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/lovego/date"
 )
 
 // Q quote a string, removing all zero byte('\000') in it.
@@ -26,14 +31,67 @@ func PatternEscape(s string) string {
 	return strings.Replace(s, `_`, `\_`, -1)
 }
 
+// Raw is a sql fragment, which V returns as it is, without quoting.
+// Never make a Raw from user input.
+type Raw string
+
+// V return the sql literal of a value.
+// Types registered by RegisterEncoder are rendered by their encoders.
+// []byte is rendered as a hex bytea literal, use Raw to pass sql fragments through.
+// Slices and arrays of scalars are rendered as postgres arrays, maps and structs as json.
+// Nil pointers, slices and the like are rendered as NULL, except nil maps as json null.
 func V(i interface{}) string {
+	if literal, ok := encode(i); ok {
+		return literal
+	}
 	// special types
 	switch v := i.(type) {
-	case []byte:
+	case SecretValue:
+		return secretMark + V(v.value)
+	case Raw:
 		return string(v)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		return `'\x` + hex.EncodeToString(v) + `'::bytea`
+	case json.RawMessage:
+		if v == nil {
+			return "NULL"
+		}
+		return Q(string(v))
 	case time.Time:
 		// all time type of postgres has 1 microsecond resolution.
 		return v.Format("'2006-01-02T15:04:05.999999Z07:00'")
+	case time.Duration:
+		return Q(interval(v)) + "::interval"
+	case net.IP:
+		if v == nil {
+			return "NULL"
+		}
+		return Q(v.String()) + "::inet"
+	case net.IPNet:
+		return Q(v.String()) + "::cidr"
+	case *net.IPNet:
+		if v == nil {
+			return "NULL"
+		}
+		return Q(v.String()) + "::cidr"
+	case date.Date:
+		// its Value return a sql literal, which would be quoted again as the bytes of a driver.Valuer.
+		return dateLiteral(v)
+	case *date.Date:
+		if v == nil {
+			return "NULL"
+		}
+		return dateLiteral(*v)
+	case big.Int:
+		return v.String()
+	case *big.Int:
+		if v == nil {
+			return "NULL"
+		}
+		return v.String()
 	case driver.Valuer:
 		return valuer(v)
 	case nil:
@@ -65,12 +123,54 @@ func V(i interface{}) string {
 		} else {
 			return V(v.Elem().Interface())
 		}
+	case reflect.Array:
+		if isUUID(v.Type()) {
+			return Q(uuid(v)) + "::uuid"
+		}
+		if array, ok := arrayLiteral(v); ok {
+			return Q(array)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return "NULL"
+		}
+		if array, ok := arrayLiteral(v); ok {
+			return Q(array)
+		}
 	}
 
 	// other types: use json
 	return Json(i)
 }
 
+// interval format d as "[-]hh:mm:ss[.ffffff]", which postgres interval accepts.
+func interval(d time.Duration) string {
+	micros := int64(d / time.Microsecond)
+	sign := ""
+	if micros < 0 {
+		sign, micros = "-", -micros
+	}
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign,
+		micros/3600e6, micros/60e6%60, micros/1e6%60,
+	)
+	if fraction := micros % 1e6; fraction != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%06d", fraction), "0")
+	}
+	return s
+}
+
+func isUUID(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Array && typ.Len() == 16 && typ.Elem().Kind() == reflect.Uint8
+}
+
+func uuid(v reflect.Value) string {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = byte(v.Index(i).Uint())
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
 // Array return data in postgres array form.
 func Array(data interface{}) string {
 	v, err := pq.Array(data).Value()
@@ -91,6 +191,13 @@ func Json(data interface{}) string {
 	return Q(string(b))
 }
 
+func dateLiteral(d date.Date) string {
+	if d.Time.IsZero() {
+		return "NULL"
+	}
+	return d.Format("'2006-01-02'")
+}
+
 var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 
 func valuer(v driver.Valuer) string {
@@ -105,7 +212,11 @@ func valuer(v driver.Valuer) string {
 	}
 	switch s := ifc.(type) {
 	case []byte:
-		return string(s)
+		// quoted like a string, because the bytes may come from user input, like json of custom types.
+		if s == nil {
+			return "NULL"
+		}
+		return Q(string(s))
 	case string:
 		if _, err := strconv.ParseFloat(s, 64); err == nil {
 			return s
@@ -0,0 +1,96 @@
+package bsql
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arrayLiteral return the postgres array literal (not quoted) of a slice or array,
+// false if any element is not a scalar, such as maps and structs.
+func arrayLiteral(v reflect.Value) (string, bool) {
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		return "", false
+	}
+	elems := make([]string, v.Len())
+	for i := range elems {
+		elem, ok := arrayElement(v.Index(i))
+		if !ok {
+			return "", false
+		}
+		elems[i] = elem
+	}
+	return "{" + strings.Join(elems, ",") + "}", true
+}
+
+func arrayElement(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "NULL", true
+		}
+		v = v.Elem()
+	}
+
+	switch e := v.Interface().(type) {
+	case time.Time:
+		return quoteArrayElement(e.Format("2006-01-02T15:04:05.999999Z07:00")), true
+	case time.Duration:
+		return quoteArrayElement(interval(e)), true
+	case net.IP:
+		if e == nil {
+			return "NULL", true
+		}
+		return quoteArrayElement(e.String()), true
+	case big.Int:
+		return e.String(), true
+	case driver.Valuer:
+		value, err := e.Value()
+		if err != nil {
+			return "", false
+		}
+		switch value := value.(type) {
+		case nil:
+			return "NULL", true
+		case []byte:
+			return quoteArrayElement(string(value)), true
+		case string:
+			return quoteArrayElement(value), true
+		default:
+			return arrayElement(reflect.ValueOf(value))
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return quoteArrayElement(v.String()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'G', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'G', -1, 64), true
+	case reflect.Array:
+		if isUUID(v.Type()) {
+			return quoteArrayElement(uuid(v)), true
+		}
+		return arrayLiteral(v)
+	case reflect.Slice:
+		return arrayLiteral(v)
+	}
+	return "", false
+}
+
+// quoteArrayElement double quote an array element, escaping '"' and '\' by '\'.
+func quoteArrayElement(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
@@ -1,7 +1,11 @@
 package bsql
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
 	"time"
 
 	"github.com/lovego/date"
@@ -31,7 +35,15 @@ func ExampleV_nil() {
 func ExampleV_bytes() {
 	var b = []byte("abc")
 	fmt.Println(V(b), V(&b))
-	// Output: abc abc
+	fmt.Println(V([]byte("a\x00b'c")), V([]byte{}), V([]byte(nil)))
+	// Output:
+	// '\x616263'::bytea '\x616263'::bytea
+	// '\x6100622763'::bytea '\x'::bytea NULL
+}
+
+func ExampleRaw() {
+	fmt.Println(V(Raw("now()")), V(Raw("DEFAULT")))
+	// Output: now() DEFAULT
 }
 
 func ExampleV_time() {
@@ -66,6 +78,20 @@ func ExampleV_driverValuer_date() {
 	// '2019-01-01' '2019-01-01'
 }
 
+type jsonValuer string
+
+func (j jsonValuer) Value() (driver.Value, error) {
+	if j == "" {
+		return []byte(nil), nil
+	}
+	return json.Marshal(string(j))
+}
+
+func ExampleV_driverValuer_bytes() {
+	fmt.Println(V(jsonValuer("it's")), V(jsonValuer("')); drop table users; --")), V(jsonValuer("")))
+	// Output: '"it''s"' '"'')); drop table users; --"' NULL
+}
+
 // basic types
 func ExampleV_string() {
 	var s = "string"
@@ -110,6 +136,59 @@ func ExampleV_json() {
 	// Output: '{"2":true,"3":false}' '{"2":true,"3":false}'
 }
 
+func ExampleV_array() {
+	fmt.Println(V([]int64{1, 2}), V([]int64{}), V([]string(nil)))
+	fmt.Println(V([]string{"a", `b"c`, `d\e`, "it's", "NULL"}))
+	fmt.Println(V([][]float64{{1.5, 2}, {3, 4}}))
+	fmt.Println(V([]*int{nil}), V([2]bool{true, false}))
+	fmt.Println(V([]interface{}{1, "a", time.Hour}))
+	fmt.Println(V([]time.Time{time.Date(2019, 6, 19, 13, 52, 8, 0, time.UTC)}))
+	fmt.Println(V([]map[string]int{{"a": 1}}))
+	// Output:
+	// '{1,2}' '{}' NULL
+	// '{"a","b\"c","d\\e","it''s","NULL"}'
+	// '{{1.5,2},{3,4}}'
+	// '{NULL}' '{true,false}'
+	// '{1,"a","01:00:00"}'
+	// '{"2019-06-19T13:52:08Z"}'
+	// '[{"a":1}]'
+}
+
+func ExampleV_interval() {
+	fmt.Println(V(90 * time.Minute))
+	fmt.Println(V(-(26*time.Hour + 3*time.Second + 1500*time.Microsecond)))
+	// Output:
+	// '01:30:00'::interval
+	// '-26:00:03.0015'::interval
+}
+
+func ExampleV_inet() {
+	_, network, _ := net.ParseCIDR("10.0.0.0/8")
+	fmt.Println(V(net.ParseIP("192.168.0.1")), V(net.ParseIP("::1")), V(network), V(*network), V(net.IP(nil)))
+	// Output: '192.168.0.1'::inet '::1'::inet '10.0.0.0/8'::cidr '10.0.0.0/8'::cidr NULL
+}
+
+func ExampleV_uuid() {
+	id := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	fmt.Println(V(id))
+	fmt.Println(V([][16]byte{id}))
+	// Output:
+	// '123e4567-e89b-12d3-a456-426614174000'::uuid
+	// '{"123e4567-e89b-12d3-a456-426614174000"}'
+}
+
+func ExampleV_bigInt() {
+	i, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	var p *big.Int
+	fmt.Println(V(i), V(*i), V(p), V([]*big.Int{i, nil}))
+	// Output: 123456789012345678901234567890 123456789012345678901234567890 NULL '{123456789012345678901234567890,NULL}'
+}
+
+func ExampleV_jsonRawMessage() {
+	fmt.Println(V(json.RawMessage(`{"name":"it's"}`)), V(json.RawMessage(nil)))
+	// Output: '{"name":"it''s"}' NULL
+}
+
 func ExampleArray() {
 	fmt.Println(Array([]int{1, 2, 3}))
 	fmt.Println(Array([]string{"a", "b", "c"}))
@@ -7,6 +7,11 @@ import (
 
 // Values return the contents following the sql keyword "VALUES"
 func Values(data interface{}) string {
+	return values(data, V)
+}
+
+func values(data interface{}, render func(interface{}) string) string {
+	data, less := unwrapOrderedMap(data)
 	value := reflect.ValueOf(data)
 	switch value.Kind() {
 	case reflect.Slice, reflect.Array:
@@ -14,50 +19,58 @@ func Values(data interface{}) string {
 		case reflect.Slice, reflect.Array:
 			var slice []string
 			for i := 0; i < value.Len(); i++ {
-				slice = append(slice, "("+SliceContents(value.Index(i))+")")
+				slice = append(slice, "("+sliceContents(value.Index(i), render)+")")
 			}
 			return strings.Join(slice, ",")
 		default:
-			return "(" + SliceContents(value) + ")"
+			return "(" + sliceContents(value, render) + ")"
 		}
 	case reflect.Map:
 		switch value.Type().Key().Kind() {
 		case reflect.Slice, reflect.Array:
 			var slice []string
-			for _, key := range value.MapKeys() {
-				slice = append(slice, "("+SliceContents(key)+")")
+			for _, key := range sortedMapKeys(value, less) {
+				slice = append(slice, "("+sliceContents(key, render)+")")
 			}
 			return strings.Join(slice, ",")
 		default:
 			var slice []string
-			for _, key := range value.MapKeys() {
-				slice = append(slice, V(key.Interface()))
+			for _, key := range sortedMapKeys(value, less) {
+				slice = append(slice, render(key.Interface()))
 			}
 			return "(" + strings.Join(slice, ",") + ")"
 		}
 	default:
-		return "(" + V(data) + ")"
+		return "(" + render(data) + ")"
 	}
 }
 
 func SingleColumnValues(data interface{}) string {
+	return singleColumnValues(data, V)
+}
+
+func singleColumnValues(data interface{}, render func(interface{}) string) string {
 	value := reflect.ValueOf(data)
 	switch value.Kind() {
 	case reflect.Slice, reflect.Array:
 		var slice []string
 		for i := 0; i < value.Len(); i++ {
-			slice = append(slice, "("+V(value.Index(i).Interface())+")")
+			slice = append(slice, "("+render(value.Index(i).Interface())+")")
 		}
 		return strings.Join(slice, ",")
 	default:
-		return "(" + V(data) + ")"
+		return "(" + render(data) + ")"
 	}
 }
 
 func SliceContents(value reflect.Value) string {
+	return sliceContents(value, V)
+}
+
+func sliceContents(value reflect.Value, render func(interface{}) string) string {
 	var slice []string
 	for i := 0; i < value.Len(); i++ {
-		slice = append(slice, V(value.Index(i).Interface()))
+		slice = append(slice, render(value.Index(i).Interface()))
 	}
 	return strings.Join(slice, ",")
 }
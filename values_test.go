@@ -22,22 +22,18 @@ func ExampleValues() {
 }
 
 func ExampleValues_map() {
-	m := map[string]bool{"('1','2')": true, "('2','1')": true}
-	result := Values(map[string]interface{}{"1": nil, "2": nil})
-	fmt.Println(m[result])
-
-	m = map[string]bool{"(1,2)": true, "(2,1)": true}
-	result = Values(map[int]interface{}{1: nil, 2: nil})
-	fmt.Println(m[result])
-
-	m = map[string]bool{"(1,2),(3,4)": true, "(3,4),(1,2)": true}
-	result = Values(map[[2]int]interface{}{[2]int{1, 2}: nil, [2]int{3, 4}: nil})
-	fmt.Println(m[result])
-
+	fmt.Println(Values(map[string]interface{}{"2": nil, "1": nil}))
+	fmt.Println(Values(map[int]interface{}{2: nil, 1: nil}))
+	fmt.Println(Values(map[[2]int]interface{}{{3, 4}: nil, {1, 2}: nil}))
+	fmt.Println(Values(OrderedMap{
+		Map:  map[int]interface{}{1: nil, 2: nil, 3: nil},
+		Less: func(a, b interface{}) bool { return a.(int) > b.(int) },
+	}))
 	// Output:
-	// true
-	// true
-	// true
+	// ('1','2')
+	// (1,2)
+	// (1,2),(3,4)
+	// (3,2,1)
 }
 
 func ExampleSingleColumnValues() {
@@ -0,0 +1,194 @@
+package bsql
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/lib/pq"
+)
+
+// ViolationMessage is the user-facing message of a constraint violation.
+type ViolationMessage struct {
+	Key   string // i18n key of the message
+	Field string // the field to report the error on, eg. "Phone".
+	// Template is a text/template executed with the conflicting column→value map,
+	// eg. "{{.phone}} already registered".
+	Template string
+	tmpl     *template.Template
+}
+
+// ViolationRegistry maps constraint names or table columns to ViolationMessages.
+type ViolationRegistry struct {
+	mutex        sync.RWMutex
+	byConstraint map[string]*ViolationMessage
+	byColumn     map[string]*ViolationMessage
+}
+
+func NewViolationRegistry() *ViolationRegistry {
+	return &ViolationRegistry{
+		byConstraint: make(map[string]*ViolationMessage),
+		byColumn:     make(map[string]*ViolationMessage),
+	}
+}
+
+// Violations is the default ViolationRegistry.
+var Violations = NewViolationRegistry()
+
+// RegisterConstraint register the message of a constraint. It panics if the template is invalid.
+func (r *ViolationRegistry) RegisterConstraint(constraint string, msg ViolationMessage) {
+	m := msg.parse()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.byConstraint[constraint] = m
+}
+
+// RegisterColumn register the message of a table column, used when no constraint matched.
+// It panics if the template is invalid.
+func (r *ViolationRegistry) RegisterColumn(table, column string, msg ViolationMessage) {
+	m := msg.parse()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.byColumn[table+"."+column] = m
+}
+
+func (msg ViolationMessage) parse() *ViolationMessage {
+	msg.tmpl = template.Must(template.New(msg.Key).Option("missingkey=zero").Parse(msg.Template))
+	return &msg
+}
+
+func (r *ViolationRegistry) lookup(constraint, table string, columns []string) *ViolationMessage {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if msg := r.byConstraint[constraint]; msg != nil && constraint != "" {
+		return msg
+	}
+	for _, column := range columns {
+		if msg := r.byColumn[table+"."+column]; msg != nil {
+			return msg
+		}
+	}
+	return nil
+}
+
+// ViolationError is a structured validation error made from a constraint violation.
+type ViolationError struct {
+	State      *SqlState
+	Table      string
+	Constraint string
+	Columns    []string
+	Values     map[string]string // conflicting column→value parsed from Detail.
+	Key        string            // i18n key
+	Field      string
+	Message    string
+	Cause      *pq.Error
+}
+
+func (e *ViolationError) Error() string {
+	return e.Message
+}
+
+func (e *ViolationError) Unwrap() error {
+	return e.Cause
+}
+
+var violationStates = []*SqlState{
+	ErrNotNullViolation, ErrForeignKeyViolation, ErrUniqueViolation,
+	ErrCheckViolation, ErrExclusionViolation,
+}
+
+// ToViolationError convert a constraint violation into a *ViolationError using the default registry.
+// It returns nil if err is not a constraint violation.
+func ToViolationError(err error) *ViolationError {
+	return Violations.ToViolationError(err)
+}
+
+// ToViolationError convert a constraint violation into a *ViolationError.
+// It returns nil if err is not a constraint violation.
+// If no message is registered, the message of the *pq.Error is used.
+func (r *ViolationRegistry) ToViolationError(err error) *ViolationError {
+	var pqError *pq.Error
+	if !As(err, &pqError) {
+		return nil
+	}
+	var state *SqlState
+	for _, s := range violationStates {
+		if s.Match(string(pqError.Code)) {
+			state = s
+		}
+	}
+	if state == nil {
+		return nil
+	}
+	columns, values := ParseViolationDetail(pqError.Detail)
+	if len(columns) == 0 && pqError.Column != "" {
+		columns = []string{pqError.Column}
+	}
+	result := &ViolationError{
+		State: state, Table: pqError.Table, Constraint: pqError.Constraint,
+		Columns: columns, Values: values, Message: pqError.Message, Cause: pqError,
+	}
+	if len(columns) > 0 {
+		result.Field = Columns2Fields(columns[:1])[0]
+	}
+	if msg := r.lookup(pqError.Constraint, pqError.Table, columns); msg != nil {
+		result.Key = msg.Key
+		if msg.Field != "" {
+			result.Field = msg.Field
+		}
+		var b bytes.Buffer
+		if msg.Template != "" && msg.tmpl.Execute(&b, values) == nil {
+			result.Message = b.String()
+		}
+	}
+	return result
+}
+
+var violationDetailRegexp = regexp.MustCompile(
+	`^Key \((.*?)\)=\((.*)\) (?:already exists|is not present in table|` +
+		`is still referenced from table|conflicts with existing key)`,
+)
+
+// ParseViolationDetail parse the Detail of a constraint violation like
+// "Key (col1, col2)=(v1, v2) already exists." into columns and a column→value map.
+// If values can't be split unambiguously, values is nil.
+func ParseViolationDetail(detail string) (columns []string, values map[string]string) {
+	m := violationDetailRegexp.FindStringSubmatch(detail)
+	if m == nil {
+		return nil, nil
+	}
+	columns = splitTopLevel(m[1])
+	valuesSlice := splitTopLevel(m[2])
+	if len(columns) == 1 {
+		valuesSlice = []string{m[2]}
+	}
+	if len(valuesSlice) != len(columns) {
+		return columns, nil
+	}
+	values = make(map[string]string, len(columns))
+	for i, column := range columns {
+		values[column] = valuesSlice[i]
+	}
+	return columns, values
+}
+
+// splitTopLevel split s by ", " out of parentheses.
+func splitTopLevel(s string) (result []string) {
+	var depth, start int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 && i+1 < len(s) && s[i+1] == ' ' {
+				result = append(result, strings.TrimSpace(s[start:i]))
+				start = i + 2
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(s[start:]))
+}
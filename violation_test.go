@@ -0,0 +1,51 @@
+package bsql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+func ExampleParseViolationDetail() {
+	fmt.Println(ParseViolationDetail("Key (phone)=(138 0000, 0000) already exists."))
+	fmt.Println(ParseViolationDetail("Key (shop_id, lower(name::text))=(1, tea) already exists."))
+	fmt.Println(ParseViolationDetail(`Key (user_id)=(9) is not present in table "users".`))
+	fmt.Println(ParseViolationDetail("Failing row contains (1, null)."))
+	// Output:
+	// [phone] map[phone:138 0000, 0000]
+	// [shop_id lower(name::text)] map[lower(name::text):tea shop_id:1]
+	// [user_id] map[user_id:9]
+	// [] map[]
+}
+
+func ExampleViolationRegistry_ToViolationError() {
+	registry := NewViolationRegistry()
+	registry.RegisterConstraint("users_phone_key", ViolationMessage{
+		Key: "user.phone.registered", Template: "{{.phone}} already registered",
+	})
+	registry.RegisterColumn("orders", "user_id", ViolationMessage{
+		Key: "order.user.not_found", Field: "UserId", Template: "user {{.user_id}} not found",
+	})
+
+	for _, pqError := range []*pq.Error{{
+		Code: "23505", Message: `duplicate key value violates unique constraint "users_phone_key"`,
+		Table: "users", Constraint: "users_phone_key", Detail: "Key (phone)=(13800000000) already exists.",
+	}, {
+		Code: "23503", Message: `insert or update on table "orders" violates foreign key constraint`,
+		Table: "orders", Constraint: "orders_user_id_fkey",
+		Detail: `Key (user_id)=(9) is not present in table "users".`,
+	}, {
+		Code: "23502", Message: `null value in column "name" violates not-null constraint`,
+		Table: "users", Column: "name", Detail: "Failing row contains (1, null).",
+	}} {
+		e := registry.ToViolationError(WrapError(pqError, "", false))
+		fmt.Printf("%s %s %s %s\n", e.State.Name, e.Key, e.Field, e)
+	}
+	fmt.Println(registry.ToViolationError(errors.New("other")))
+	// Output:
+	// unique_violation user.phone.registered Phone 13800000000 already registered
+	// foreign_key_violation order.user.not_found UserId user 9 not found
+	// not_null_violation  Name null value in column "name" violates not-null constraint
+	// <nil>
+}
@@ -0,0 +1,151 @@
+package bsql
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WhereFrame is a frame of the context stack in pq.Error.Where, such as:
+//
+//	PL/pgSQL function add_score(integer) line 5 at SQL statement
+//	SQL function "total_score" statement 1
+type WhereFrame struct {
+	Language string `json:"language,omitempty"` // "PL/pgSQL" or "SQL", empty if unrecognized.
+	Function string `json:"function,omitempty"` // the function signature, like "add_score(integer)".
+	// Line is the line number in the PL/pgSQL function body,
+	// or the statement number in the SQL function, 0 if unknown.
+	Line   int    `json:"line,omitempty"`
+	Action string `json:"action,omitempty"` // what the frame is doing, like "SQL statement", "RAISE".
+	// Statement is the sql statement or expression being executed by the frame, if reported.
+	Statement string `json:"statement,omitempty"`
+	Text      string `json:"text"` // the original line of the frame.
+}
+
+var (
+	whereStatementRegexp = regexp.MustCompile(`^(?:SQL statement|SQL expression|PL/pgSQL expression) "`)
+	whereLineRegexp      = regexp.MustCompile(`^line (\d+)(?: at (.*))?$`)
+	whereStatementNumber = regexp.MustCompile(`^statement (\d+)$`)
+)
+
+// ParseWhere parse pq.Error.Where into frames, the innermost frame comes first.
+// The "SQL statement" lines are attached to the frame executing them as Statement.
+func ParseWhere(where string) []WhereFrame {
+	var frames []WhereFrame
+	var statement string
+	lines := strings.Split(where, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if loc := whereStatementRegexp.FindStringIndex(line); loc != nil {
+			// the statement is quoted without escaping, and may span multiple lines.
+			text := line[loc[1]:]
+			for !strings.HasSuffix(text, `"`) && i+1 < len(lines) {
+				i++
+				text += "\n" + lines[i]
+			}
+			statement = strings.TrimSuffix(text, `"`)
+			continue
+		}
+		frame := parseWhereFrame(line)
+		frame.Statement, statement = statement, ""
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+func parseWhereFrame(line string) WhereFrame {
+	frame := WhereFrame{Text: line}
+	var rest string
+	if strings.HasPrefix(line, "PL/pgSQL function ") {
+		frame.Language, rest = "PL/pgSQL", strings.TrimPrefix(line, "PL/pgSQL function ")
+	} else if strings.HasPrefix(line, "SQL function ") {
+		frame.Language, rest = "SQL", strings.TrimPrefix(line, "SQL function ")
+	} else {
+		return frame
+	}
+	frame.Function, rest = splitWhereFunction(rest)
+	if m := whereLineRegexp.FindStringSubmatch(rest); m != nil {
+		frame.Line, _ = strconv.Atoi(m[1])
+		frame.Action = m[2]
+	} else if m := whereStatementNumber.FindStringSubmatch(rest); m != nil {
+		frame.Line, _ = strconv.Atoi(m[1])
+	} else {
+		frame.Action = rest
+	}
+	return frame
+}
+
+// splitWhereFunction split the leading function signature, which may be quoted or have arguments.
+func splitWhereFunction(s string) (string, string) {
+	end := len(s)
+	if strings.HasPrefix(s, `"`) {
+		if i := strings.Index(s[1:], `"`); i >= 0 {
+			end = i + 2
+		}
+	} else if space, paren := strings.IndexByte(s, ' '), strings.IndexByte(s, '('); paren >= 0 &&
+		(space < 0 || paren < space) {
+		if i := strings.IndexByte(s[paren:], ')'); i >= 0 {
+			end = paren + i + 1
+		}
+	} else if space >= 0 {
+		end = space
+	}
+	return strings.Trim(s[:end], `"`), strings.TrimSpace(s[end:])
+}
+
+// Name return the function name without arguments and schema.
+func (f WhereFrame) Name() string {
+	name := f.Function
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Traceback render the frames with the outermost first, like a Python traceback.
+func Traceback(frames []WhereFrame) string {
+	if len(frames) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Traceback (most recent call last):")
+	for i := len(frames) - 1; i >= 0; i-- {
+		b.WriteString("\n  " + frames[i].Text)
+		if frames[i].Statement != "" {
+			b.WriteString("\n    " + strings.Replace(frames[i].Statement, "\n", "\n    ", -1))
+		}
+	}
+	return b.String()
+}
+
+// callSite return the rune range of the outermost function call of frames in sql,
+// or -1, -1 if not found.
+func callSite(sql string, frames []WhereFrame) (int, int) {
+	if len(frames) == 0 {
+		return -1, -1
+	}
+	var re *regexp.Regexp
+	if name := frames[len(frames)-1].Name(); name == "inline_code_block" {
+		re = regexp.MustCompile(`(?i)\bdo\b`)
+	} else if name != "" {
+		re = regexp.MustCompile(`(?i)(?:^|[^\w$])("?` + regexp.QuoteMeta(name) + `"?)\s*\(`)
+	} else {
+		return -1, -1
+	}
+	loc := re.FindStringSubmatchIndex(sql)
+	if loc == nil {
+		return -1, -1
+	}
+	start, end := loc[0], loc[1]
+	if len(loc) > 2 {
+		start, end = loc[2], loc[3]
+	}
+	return utf8.RuneCountInString(sql[:start]), utf8.RuneCountInString(sql[:end])
+}
@@ -0,0 +1,33 @@
+package bsql
+
+import (
+	"fmt"
+)
+
+func ExampleParseWhere() {
+	where := "SQL statement \"insert into scores (student_id, score)\n  values (id, 101)\"\n" +
+		"PL/pgSQL function add_score(integer) line 5 at SQL statement\n" +
+		"SQL function \"total_score\" statement 1\n" +
+		"PL/pgSQL function public.enroll(integer, text) line 12 at PERFORM"
+	for _, frame := range ParseWhere(where) {
+		fmt.Printf("%s|%s|%s|%d|%s|%q\n",
+			frame.Language, frame.Function, frame.Name(), frame.Line, frame.Action, frame.Statement)
+	}
+	// Output:
+	// PL/pgSQL|add_score(integer)|add_score|5|SQL statement|"insert into scores (student_id, score)\n  values (id, 101)"
+	// SQL|total_score|total_score|1||""
+	// PL/pgSQL|public.enroll(integer, text)|enroll|12|PERFORM|""
+}
+
+func ExampleTraceback() {
+	fmt.Println(Traceback(ParseWhere(
+		"SQL statement \"insert into scores values (id, 101)\"\n" +
+			"PL/pgSQL function add_score(integer) line 5 at SQL statement\n" +
+			"PL/pgSQL function enroll(integer) line 3 at PERFORM",
+	)))
+	// Output:
+	// Traceback (most recent call last):
+	//   PL/pgSQL function enroll(integer) line 3 at PERFORM
+	//   PL/pgSQL function add_score(integer) line 5 at SQL statement
+	//     insert into scores values (id, 101)
+}